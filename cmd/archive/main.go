@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/logging"
+	"github.com/ayushsarode/task-scheduler/internal/retention"
+)
+
+const usage = `Usage: archive restore <path>
+
+Loads archived task results back into the database. <path> may be the
+archive root, a task directory or a single task-month directory.
+Results of tasks that no longer exist are skipped and reported.`
+
+func main() {
+	if len(os.Args) != 3 || os.Args[1] != "restore" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := logging.Init(cfg.Log); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+
+	database, err := db.NewPostgresDB(cfg.GetDSN())
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	blobStore, err := blobstore.NewFSStore(cfg.Storage.BlobDir)
+	if err != nil {
+		slog.Error("Failed to open blob store", "error", err)
+		os.Exit(1)
+	}
+
+	summary, err := retention.Restore(db.NewRepository(database), blobStore, os.Args[2])
+	if err != nil {
+		slog.Error("Restore failed", "restored", summary.Restored, "error", err)
+		os.Exit(1)
+	}
+	if summary.Skipped > 0 {
+		slog.Warn("Skipped results of deleted tasks", "count", summary.Skipped, "task_ids", summary.SkippedTasks)
+	}
+	slog.Info("Restored results", "count", summary.Restored)
+}
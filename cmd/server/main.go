@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,13 +11,21 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ayushsarode/task-scheduler/internal/alerting"
 	"github.com/ayushsarode/task-scheduler/internal/api"
+	"github.com/ayushsarode/task-scheduler/internal/audit"
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
 	"github.com/ayushsarode/task-scheduler/internal/config"
 	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/logging"
+	"github.com/ayushsarode/task-scheduler/internal/metrics"
+	"github.com/ayushsarode/task-scheduler/internal/retention"
 	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/secrets"
 	"github.com/ayushsarode/task-scheduler/internal/services"
-	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func main() {
@@ -27,47 +36,140 @@ func main() {
 	}
 
 	// Initialize logger
-	utils.InitLogger(cfg.Log.Level)
-	utils.Info("Starting Task Scheduler Server...")
+	if err := logging.Init(cfg.Log); err != nil {
+		log.Fatalf("Failed to initialize logging: %v", err)
+	}
+	slog.Info("Starting Task Scheduler Server")
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
+	if err != nil {
+		fatal("Failed to initialize tracing", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
 
 	// Connect to database
 	database, err := db.NewPostgresDB(cfg.GetDSN())
 	if err != nil {
-		utils.Fatal("Failed to connect to database: %v", err)
+		fatal("Failed to connect to database", err)
 	}
 	defer database.Close()
 
 	// Run database migrations
 	migrationsPath := filepath.Join("internal", "db", "migrations")
 	if err := database.RunMigrations(migrationsPath); err != nil {
-		utils.Fatal("Failed to run database migrations: %v", err)
+		fatal("Failed to run database migrations", err)
 	}
 
 	// Initialize repository
 	repo := db.NewRepository(database)
 
+	// Initialize secrets keyring
+	keyring, err := secrets.NewKeyring(cfg.Secrets.MasterKey, cfg.Secrets.PreviousKeys)
+	if err != nil {
+		fatal("Failed to initialize secrets keyring", err)
+	}
+	if keyring == nil {
+		slog.Warn("SECRETS_MASTER_KEY is not set, secrets store is disabled")
+	}
+	secretService := services.NewSecretService(repo, keyring)
+
+	// Initialize blob store for large response bodies
+	blobStore, err := blobstore.NewFSStore(cfg.Storage.BlobDir)
+	if err != nil {
+		fatal("Failed to initialize blob store", err)
+	}
+
+	// Initialize alerting; notifications are subject to the same egress
+	// policy as task requests
+	egress, err := scheduler.NewEgressPolicy(cfg.Executor.Egress)
+	if err != nil {
+		fatal("Failed to initialize egress policy", err)
+	}
+	alerter := alerting.NewAlerter(repo, secretService, egress.DialContext, cfg.Alerting)
+
 	// Initialize scheduler
-	taskScheduler := scheduler.NewScheduler(repo)
+	taskScheduler, err := scheduler.NewScheduler(repo, secretService, blobStore, alerter, cfg.Executor)
+	if err != nil {
+		fatal("Failed to initialize scheduler", err)
+	}
 
 	// Initialize services
 	taskService := services.NewTaskService(repo, taskScheduler)
-	resultService := services.NewResultService(repo)
+	resultService := services.NewResultService(repo, blobStore)
+	authProfileService := services.NewAuthProfileService(repo)
+	tlsProfileService := services.NewTLSProfileService(repo, secretService, cfg.Executor.AllowInsecureTLS)
+	channelService := services.NewChannelService(repo, alerter, egress)
+	checkinService := services.NewCheckinService(repo, alerter)
+	auditService := services.NewAuditService(repo)
+	apiKeyService := services.NewAPIKeyService(repo, cfg.Auth)
+	if !cfg.Auth.Enabled {
+		slog.Warn("API_AUTH_ENABLED is not set, the API accepts unauthenticated requests")
+	}
+	adminService := services.NewAdminService(taskScheduler)
+	healthService := services.NewHealthService(repo, taskScheduler, migrationsPath, cfg.Health.CheckTimeout)
 
 	// Start scheduler
 	if err := taskScheduler.Start(); err != nil {
-		utils.Fatal("Failed to start scheduler: %v", err)
+		fatal("Failed to start scheduler", err)
 	}
 	defer taskScheduler.Stop()
+	metrics.RegisterCronEntries(taskScheduler.JobCount)
+
+	// Start result retention and partition maintenance job
+	pruner := retention.NewPruner(repo, blobStore, cfg.Retention)
+	pruner.Start()
+	defer pruner.Stop()
+
+	// Start dead man's switch for tasks with a heartbeat policy
+	watchdog := alerting.NewWatchdog(repo, alerter, cfg.Alerting.HeartbeatCheckInterval)
+	watchdog.Start()
+	defer watchdog.Stop()
 
 	// Setup Gin router
 	if cfg.Log.Level != "debug" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	router := gin.Default()
+	router := gin.New()
+	// Client IPs are recorded in the audit log, so forwarding headers are
+	// only believed from configured proxies
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal("Invalid TRUSTED_PROXIES", err)
+	}
+	// Ping tokens identify check-in monitors, so they are kept out of spans
+	// and access logs
+	router.Use(logging.RedactPathParams("token"))
+	router.Use(otelgin.Middleware(cfg.Tracing.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		// Leave scrapes and probes out of traces
+		switch r.URL.Path {
+		case "/metrics", "/health", "/livez", "/readyz":
+			return false
+		}
+		return true
+	})))
+	router.Use(logging.GinMiddleware(), gin.Recovery(), audit.GinMiddleware())
 
 	// Setup API routes
-	api.SetupRoutes(router, taskService, resultService)
+	api.SetupRoutes(router, api.Services{
+		Tasks:        taskService,
+		Results:      resultService,
+		Secrets:      secretService,
+		AuthProfiles: authProfileService,
+		TLSProfiles:  tlsProfileService,
+		Channels:     channelService,
+		Checkins:     checkinService,
+		Audit:        auditService,
+		APIKeys:      apiKeyService,
+		Admin:        adminService,
+		Health:       healthService,
+	})
 
 	// Create HTTP server
 	server := &http.Server{
@@ -77,9 +179,9 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		utils.Info("Server starting on %s", cfg.GetServerAddress())
+		slog.Info("Server starting", "address", cfg.GetServerAddress())
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			utils.Fatal("Failed to start server: %v", err)
+			fatal("Failed to start server", err)
 		}
 	}()
 
@@ -88,15 +190,21 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	utils.Info("Shutting down server...")
+	slog.Info("Shutting down server")
 
 	// Graceful shutdown with 30 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		utils.Fatal("Server forced to shutdown: %v", err)
+		fatal("Server forced to shutdown", err)
 	}
 
-	utils.Info("Server exited")
+	slog.Info("Server exited")
+}
+
+// fatal logs msg with err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
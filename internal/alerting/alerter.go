@@ -0,0 +1,413 @@
+// Package alerting evaluates task alert rules after each run and delivers
+// notifications to webhook, Slack and email channels.
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/logging"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var logger = logging.For("alerting")
+
+// retryBackoff is multiplied by the attempt number between delivery attempts.
+const retryBackoff = time.Second
+
+// SecretResolver looks up secret values by name.
+type SecretResolver interface {
+	ResolveSecret(name string) (string, error)
+}
+
+// DialFunc opens outgoing connections. Notifications use the executor's
+// egress policy so that channels cannot reach blocked destinations.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// pending is a notification that a rule transition produced.
+type pending struct {
+	rule         *models.AlertRule
+	notification models.Notification
+}
+
+type Alerter struct {
+	repo    *db.Repository
+	secrets SecretResolver
+	dial    DialFunc
+	client  *http.Client
+	cfg     config.AlertingConfig
+
+	// mu serializes rule evaluation so concurrent runs of a task cannot both
+	// see a rule as not yet notified
+	mu sync.Mutex
+	// observed is the run count of the latest run evaluated for each task
+	observed map[uuid.UUID]int64
+}
+
+func NewAlerter(repo *db.Repository, secrets SecretResolver, dial DialFunc, cfg config.AlertingConfig) *Alerter {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dial
+
+	return &Alerter{
+		repo:     repo,
+		secrets:  secrets,
+		dial:     dial,
+		client:   &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		cfg:      cfg,
+		observed: make(map[uuid.UUID]int64),
+	}
+}
+
+// Observe evaluates the task's alert rules against a saved result, using the
+// run counters saving it returned, and delivers any notifications they
+// produce.
+func (a *Alerter) Observe(ctx context.Context, task *models.Task, result *models.TaskResult, counters *models.TaskRunCounters) {
+	if len(task.Alerts) == 0 {
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "Alerter.Observe")
+	notifications, err := a.evaluate(ctx, task, result, counters)
+	defer tracing.End(span, err)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to evaluate alert rules", "error", err)
+		return
+	}
+
+	for _, p := range notifications {
+		for _, channelName := range p.rule.Channels {
+			a.notify(ctx, channelName, &p.notification)
+		}
+	}
+}
+
+// evaluate updates the alert state of each of the task's rules and returns
+// the notifications to send. Rules are evaluated in a fixed order so that the
+// recovery rule sees the consecutive_failures rule's updated state.
+//
+// Runs are evaluated in run order: a run that was saved after a later run,
+// or is observed after a run saved after it, is skipped, so it cannot undo
+// the later run's state.
+func (a *Alerter) evaluate(ctx context.Context, task *models.Task, result *models.TaskResult, counters *models.TaskRunCounters) ([]pending, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.inOrder(task.ID, result, counters) {
+		logger.DebugContext(ctx, "Skipping alert evaluation of an out of order run", "task_id", task.ID, "result_id", result.ID)
+		return nil, nil
+	}
+
+	repo := a.repo.WithContext(ctx)
+	states, err := repo.GetAlertStates(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	stateFor := func(alertType models.AlertType) *models.AlertState {
+		if state, ok := states[alertType]; ok {
+			return state
+		}
+		state := &models.AlertState{TaskID: task.ID, RuleType: alertType}
+		states[alertType] = state
+		return state
+	}
+
+	now := time.Now()
+	var notifications []pending
+	order := []models.AlertType{models.AlertConsecutiveFailures, models.AlertLatency, models.AlertRecovery}
+	for _, alertType := range order {
+		rule := task.Alerts.Rule(alertType)
+		if rule == nil {
+			continue
+		}
+		state := stateFor(alertType)
+		before := *state
+
+		var event models.AlertEvent
+		var summary string
+		switch alertType {
+		case models.AlertConsecutiveFailures:
+			if result.Success {
+				state.Firing = false
+			} else if counters.ConsecutiveFailures >= int64(rule.Failures) {
+				event = a.fire(rule, state, now)
+				summary = fmt.Sprintf("Task %q has failed %d times in a row", task.Name, counters.ConsecutiveFailures)
+			}
+
+		case models.AlertLatency:
+			if result.DurationMs > rule.LatencyMs {
+				event = a.fire(rule, state, now)
+				summary = fmt.Sprintf("Task %q took %dms, over the %dms threshold", task.Name, result.DurationMs, rule.LatencyMs)
+			} else {
+				state.Firing = false
+			}
+
+		case models.AlertRecovery:
+			// The rule is armed by a failure and fires on the next success
+			if !result.Success {
+				armed := true
+				if failures := task.Alerts.Rule(models.AlertConsecutiveFailures); failures != nil {
+					armed = stateFor(models.AlertConsecutiveFailures).Firing
+				}
+				if armed && !state.Firing {
+					state.Firing = true
+					state.FiredAt = &now
+				}
+			} else if state.Firing {
+				state.Firing = false
+				if a.throttled(state, now) {
+					break
+				}
+				state.LastNotifiedAt = &now
+				event = models.AlertResolved
+				summary = fmt.Sprintf("Task %q succeeded again", task.Name)
+				if state.FiredAt != nil {
+					summary += fmt.Sprintf(" after failing since %s", state.FiredAt.UTC().Format(time.RFC3339))
+				}
+			}
+		}
+
+		if *state != before {
+			if err := repo.SaveAlertState(state); err != nil {
+				return nil, err
+			}
+		}
+		if event == "" {
+			continue
+		}
+
+		notification := models.Notification{
+			Event:      event,
+			RuleType:   alertType,
+			TaskID:     task.ID,
+			TaskName:   task.Name,
+			ResultID:   &result.ID,
+			Summary:    summary,
+			StatusCode: result.StatusCode,
+			DurationMs: result.DurationMs,
+			RunAt:      &result.RunAt,
+			TraceID:    result.TraceID,
+		}
+		if result.ErrorMessage != nil {
+			notification.Error = *result.ErrorMessage
+		}
+		notifications = append(notifications, pending{rule: rule, notification: notification})
+	}
+
+	return notifications, nil
+}
+
+// inOrder reports whether result is the latest run of the task observed so
+// far, and records it as observed. a.mu must be held.
+func (a *Alerter) inOrder(taskID uuid.UUID, result *models.TaskResult, counters *models.TaskRunCounters) bool {
+	if counters.RunCount <= a.observed[taskID] {
+		return false
+	}
+	a.observed[taskID] = counters.RunCount
+	return counters.LastRunAt == nil || !result.RunAt.Before(*counters.LastRunAt)
+}
+
+// ObserveOverdue updates the state of the task's overdue rule, if it has one,
+// and notifies its channels when the task has become overdue, and again when
+// it is no longer overdue. lastRun is nil when the task has never run.
+func (a *Alerter) ObserveOverdue(ctx context.Context, task *models.Task, lastRun *time.Time, expectedBy time.Time, overdue bool) {
+	rule := task.Alerts.Rule(models.AlertOverdue)
+	if rule == nil {
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "Alerter.ObserveOverdue")
+	event, err := a.evaluateOverdue(ctx, task, rule, overdue)
+	defer tracing.End(span, err)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to evaluate overdue alert rule", "task_id", task.ID, "error", err)
+		return
+	}
+	if event == "" {
+		return
+	}
+
+	var summary string
+	switch {
+	case event == models.AlertResolved && lastRun != nil:
+		summary = fmt.Sprintf("Task %q is no longer overdue; it last ran at %s", task.Name, lastRun.UTC().Format(time.RFC3339))
+	case event == models.AlertResolved:
+		summary = fmt.Sprintf("Task %q is no longer overdue", task.Name)
+	case lastRun != nil:
+		summary = fmt.Sprintf("Task %q was expected to run by %s and last ran at %s", task.Name, expectedBy.UTC().Format(time.RFC3339), lastRun.UTC().Format(time.RFC3339))
+	default:
+		summary = fmt.Sprintf("Task %q was expected to run by %s and has never run", task.Name, expectedBy.UTC().Format(time.RFC3339))
+	}
+
+	notification := &models.Notification{
+		Event:    event,
+		RuleType: models.AlertOverdue,
+		TaskID:   task.ID,
+		TaskName: task.Name,
+		Summary:  summary,
+		RunAt:    lastRun,
+	}
+	if event == models.AlertFiring {
+		notification.ExpectedBy = &expectedBy
+	}
+	for _, channelName := range rule.Channels {
+		a.notify(ctx, channelName, notification)
+	}
+}
+
+func (a *Alerter) evaluateOverdue(ctx context.Context, task *models.Task, rule *models.AlertRule, overdue bool) (models.AlertEvent, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	repo := a.repo.WithContext(ctx)
+	states, err := repo.GetAlertStates(task.ID)
+	if err != nil {
+		return "", err
+	}
+	state, ok := states[models.AlertOverdue]
+	if !ok {
+		state = &models.AlertState{TaskID: task.ID, RuleType: models.AlertOverdue}
+	}
+	before := *state
+
+	now := time.Now()
+	var event models.AlertEvent
+	if overdue {
+		event = a.fire(rule, state, now)
+	} else if state.Firing {
+		state.Firing = false
+		// Channels that were told the task is overdue hear that it recovered
+		notified := state.LastNotifiedAt != nil && state.FiredAt != nil && !state.LastNotifiedAt.Before(*state.FiredAt)
+		if notified && !a.throttled(state, now) {
+			state.LastNotifiedAt = &now
+			event = models.AlertResolved
+		}
+	}
+
+	if *state != before {
+		if err := repo.SaveAlertState(state); err != nil {
+			return "", err
+		}
+	}
+	return event, nil
+}
+
+// fire marks a rule as firing and returns AlertFiring if its channels should
+// be notified: when it starts firing, or when it has kept firing for
+// RepeatAfterMinutes since the last notification. Either is subject to the
+// throttle.
+func (a *Alerter) fire(rule *models.AlertRule, state *models.AlertState, now time.Time) models.AlertEvent {
+	notify := !state.Firing
+	if !state.Firing {
+		state.Firing = true
+		state.FiredAt = &now
+	} else if rule.RepeatAfterMinutes > 0 && state.LastNotifiedAt != nil {
+		notify = now.Sub(*state.LastNotifiedAt) >= time.Duration(rule.RepeatAfterMinutes)*time.Minute
+	}
+	if !notify || a.throttled(state, now) {
+		return ""
+	}
+	state.LastNotifiedAt = &now
+	return models.AlertFiring
+}
+
+// throttled reports whether the rule notified less than the throttle interval ago.
+func (a *Alerter) throttled(state *models.AlertState, now time.Time) bool {
+	return state.LastNotifiedAt != nil && now.Sub(*state.LastNotifiedAt) < a.cfg.Throttle
+}
+
+// notify delivers a notification to the named channel, retrying failed
+// attempts. Every attempt is recorded.
+func (a *Alerter) notify(ctx context.Context, channelName string, notification *models.Notification) {
+	ctx = logging.WithAttrs(ctx, "channel", channelName, "rule_type", notification.RuleType)
+
+	channel, err := a.repo.WithContext(ctx).GetChannelByName(channelName)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load notification channel", "error", err)
+		return
+	}
+
+	deliveries, err := a.deliver(ctx, channel, notification)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to deliver notification", "attempts", len(deliveries), "error", err)
+		return
+	}
+	logger.InfoContext(ctx, "Delivered notification", "event", notification.Event, "attempts", len(deliveries))
+}
+
+// SendTest sends a test notification to channel and returns its delivery
+// attempts. The error is that of the last attempt when none succeeded.
+func (a *Alerter) SendTest(ctx context.Context, channel *models.NotificationChannel) ([]models.AlertDelivery, error) {
+	notification := &models.Notification{
+		Event:   models.AlertTest,
+		Summary: fmt.Sprintf("Test notification for channel %q", channel.Name),
+		TraceID: tracing.TraceID(ctx),
+	}
+	return a.deliver(ctx, channel, notification)
+}
+
+func (a *Alerter) deliver(ctx context.Context, channel *models.NotificationChannel, notification *models.Notification) (deliveries []models.AlertDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "Alerter.deliver",
+		attribute.String("alert.channel", channel.Name),
+		attribute.String("alert.channel_type", string(channel.Type)),
+		attribute.String("alert.event", string(notification.Event)),
+	)
+	defer func() { tracing.End(span, err) }()
+	repo := a.repo.WithContext(ctx)
+
+	deliveries = []models.AlertDelivery{}
+	for attempt := 1; attempt <= a.cfg.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-time.After(time.Duration(attempt-1) * retryBackoff):
+			case <-ctx.Done():
+				return deliveries, ctx.Err()
+			}
+		}
+
+		start := time.Now()
+		var statusCode int
+		statusCode, err = a.send(ctx, channel, notification)
+
+		delivery := models.AlertDelivery{
+			ID:          uuid.New(),
+			ChannelName: channel.Name,
+			RuleType:    notification.RuleType,
+			Event:       notification.Event,
+			Attempt:     attempt,
+			Success:     err == nil,
+			StatusCode:  statusCode,
+			DurationMs:  time.Since(start).Milliseconds(),
+			AttemptedAt: start,
+		}
+		if notification.TaskID != uuid.Nil {
+			delivery.TaskID = &notification.TaskID
+			delivery.ResultID = notification.ResultID
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		if saveErr := repo.CreateAlertDelivery(&delivery); saveErr != nil {
+			logger.ErrorContext(ctx, "Failed to record alert delivery", "error", saveErr)
+		}
+		deliveries = append(deliveries, delivery)
+
+		if err == nil {
+			return deliveries, nil
+		}
+	}
+
+	return deliveries, err
+}
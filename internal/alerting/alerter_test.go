@@ -0,0 +1,68 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestAlerterInOrder(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	// observation is a run as Observe sees it: when it ran, and the run count
+	// and last run time that saving it left
+	type observation struct {
+		runAt    time.Time
+		runCount int64
+		lastRun  time.Time
+	}
+	tests := []struct {
+		name         string
+		observations []observation
+		want         []bool
+	}{
+		{
+			name: "in order",
+			observations: []observation{
+				{runAt: at(0), runCount: 1, lastRun: at(0)},
+				{runAt: at(1), runCount: 2, lastRun: at(1)},
+			},
+			want: []bool{true, true},
+		},
+		{
+			name: "observed after a run saved after it",
+			observations: []observation{
+				{runAt: at(1), runCount: 2, lastRun: at(1)},
+				{runAt: at(0), runCount: 1, lastRun: at(0)},
+			},
+			want: []bool{true, false},
+		},
+		{
+			name: "saved after a later run",
+			observations: []observation{
+				{runAt: at(1), runCount: 1, lastRun: at(1)},
+				{runAt: at(0), runCount: 2, lastRun: at(1)},
+				{runAt: at(2), runCount: 3, lastRun: at(2)},
+			},
+			want: []bool{true, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerter := NewAlerter(nil, fakeSecrets{}, nil, config.AlertingConfig{})
+			taskID := uuid.New()
+			for i, o := range tt.observations {
+				lastRun := o.lastRun
+				counters := &models.TaskRunCounters{RunCount: o.runCount, LastRunAt: &lastRun}
+				if got := alerter.inOrder(taskID, &models.TaskResult{RunAt: o.runAt}, counters); got != tt.want[i] {
+					t.Errorf("observation %d: inOrder() = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// send makes one attempt to deliver a notification to channel. It returns
+// the HTTP status code for webhook and Slack channels.
+func (a *Alerter) send(ctx context.Context, channel *models.NotificationChannel, notification *models.Notification) (int, error) {
+	switch channel.Type {
+	case models.ChannelWebhook:
+		return a.post(ctx, channel.Config, notification)
+	case models.ChannelSlack:
+		return a.post(ctx, channel.Config, map[string]string{"text": message(notification)})
+	case models.ChannelEmail:
+		return 0, a.sendMail(ctx, channel.Config, notification)
+	default:
+		return 0, fmt.Errorf("unsupported channel type %q", channel.Type)
+	}
+}
+
+// message renders a notification as a single line of text.
+func message(notification *models.Notification) string {
+	text := fmt.Sprintf("[%s] %s", strings.ToUpper(string(notification.Event)), notification.Summary)
+	if notification.Error != "" {
+		text += ": " + notification.Error
+	} else if notification.StatusCode != 0 {
+		text += fmt.Sprintf(" (HTTP %d)", notification.StatusCode)
+	}
+	return text
+}
+
+// post sends payload as JSON to the channel's URL. Non-2xx responses are
+// errors. Errors never include the URL, which may hold a secret.
+func (a *Alerter) post(ctx context.Context, cfg models.ChannelConfig, payload interface{}) (int, error) {
+	target := cfg.URL
+	if cfg.URLSecretName != "" {
+		resolved, err := a.secrets.ResolveSecret(cfg.URLSecretName)
+		if err != nil {
+			return 0, err
+		}
+		target = resolved
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, withoutURL(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return 0, withoutURL(err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// withoutURL replaces a *url.Error, whose message includes the URL, with
+// its underlying error.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s request failed: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// sendMail delivers a notification over SMTP, upgrading the connection with
+// STARTTLS when configured and authenticating when a username is set.
+func (a *Alerter) sendMail(ctx context.Context, cfg models.ChannelConfig, notification *models.Notification) error {
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = 25
+	}
+
+	if a.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.cfg.Timeout)
+		defer cancel()
+	}
+	conn, err := a.dial(ctx, "tcp", net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if cfg.StartTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.SMTPHost}); err != nil {
+			return err
+		}
+	}
+	if cfg.Username != "" {
+		password, err := a.secrets.ResolveSecret(cfg.PasswordSecretName)
+		if err != nil {
+			return err
+		}
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, password, cfg.SMTPHost)); err != nil {
+			return err
+		}
+	}
+
+	// The envelope takes bare addresses; the headers keep any display names
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return err
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range cfg.To {
+		rcpt, err := mail.ParseAddress(to)
+		if err != nil {
+			return err
+		}
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(mailBody(cfg, notification)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// headerSanitizer keeps task names and errors from breaking out of a header.
+var headerSanitizer = strings.NewReplacer("\r", " ", "\n", " ")
+
+func mailBody(cfg models.ChannelConfig, notification *models.Notification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerSanitizer.Replace(message(notification)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+
+	fmt.Fprintf(&b, "%s\r\n\r\n", notification.Summary)
+	if notification.TaskName != "" {
+		fmt.Fprintf(&b, "Task: %s (%s)\r\n", notification.TaskName, notification.TaskID)
+	}
+	if notification.ResultID != nil {
+		fmt.Fprintf(&b, "Run: %s at %s\r\n", notification.ResultID, notification.RunAt.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&b, "Duration: %dms\r\n", notification.DurationMs)
+	}
+	if notification.ExpectedBy != nil {
+		fmt.Fprintf(&b, "Expected by: %s\r\n", notification.ExpectedBy.UTC().Format(time.RFC3339))
+	}
+	if notification.StatusCode != 0 {
+		fmt.Fprintf(&b, "Status: %d\r\n", notification.StatusCode)
+	}
+	if notification.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\r\n", notification.Error)
+	}
+	if notification.TraceID != "" {
+		fmt.Fprintf(&b, "Trace: %s\r\n", notification.TraceID)
+	}
+	return b.Bytes()
+}
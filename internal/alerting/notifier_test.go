@@ -0,0 +1,294 @@
+package alerting
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/google/uuid"
+)
+
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) ResolveSecret(name string) (string, error) {
+	if value, ok := f[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret not found")
+}
+
+// newTestAlerter returns an alerter whose egress policy blocks private
+// addresses but allows loopback, so channels can reach local test servers.
+func newTestAlerter(t *testing.T, secrets fakeSecrets) *Alerter {
+	t.Helper()
+	egress, err := scheduler.NewEgressPolicy(config.EgressConfig{
+		BlockPrivate: true,
+		AllowCIDRs:   []string{"127.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAlerter(nil, secrets, egress.DialContext, config.AlertingConfig{Timeout: 5 * time.Second, MaxAttempts: 1})
+}
+
+func testNotification() *models.Notification {
+	return &models.Notification{
+		Event:      models.AlertFiring,
+		Summary:    "Task \"nightly\" has failed 3 times in a row",
+		TaskID:     uuid.New(),
+		TaskName:   "nightly",
+		StatusCode: 503,
+	}
+}
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func captureServer(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+	requests := make(chan capturedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestSendWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		useSecret  bool
+		wantErr    bool
+		wantStatus int
+	}{
+		{name: "delivered", status: http.StatusOK, wantStatus: http.StatusOK},
+		{name: "url from secret", status: http.StatusAccepted, useSecret: true, wantStatus: http.StatusAccepted},
+		{name: "non-2xx", status: http.StatusInternalServerError, wantErr: true, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := captureServer(t, tt.status)
+			cfg := models.ChannelConfig{Headers: map[string]string{"Authorization": "Bearer token"}}
+			secrets := fakeSecrets{}
+			if tt.useSecret {
+				cfg.URLSecretName = "webhook-url"
+				secrets["webhook-url"] = server.URL
+			} else {
+				cfg.URL = server.URL
+			}
+			channel := &models.NotificationChannel{Name: "ops", Type: models.ChannelWebhook, Config: cfg}
+			notification := testNotification()
+
+			status, err := newTestAlerter(t, secrets).send(context.Background(), channel, notification)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("send() status = %d, want %d", status, tt.wantStatus)
+			}
+
+			req := <-requests
+			if got := req.header.Get("Authorization"); got != "Bearer token" {
+				t.Errorf("Authorization header = %q, want %q", got, "Bearer token")
+			}
+			if got := req.header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type header = %q, want application/json", got)
+			}
+			var received models.Notification
+			if err := json.Unmarshal(req.body, &received); err != nil {
+				t.Fatalf("body is not a notification: %v", err)
+			}
+			if received.TaskID != notification.TaskID || received.Summary != notification.Summary {
+				t.Errorf("received notification %+v, want %+v", received, notification)
+			}
+		})
+	}
+}
+
+func TestSendSlack(t *testing.T) {
+	server, requests := captureServer(t, http.StatusOK)
+	channel := &models.NotificationChannel{Name: "ops-slack", Type: models.ChannelSlack, Config: models.ChannelConfig{URL: server.URL}}
+
+	if _, err := newTestAlerter(t, fakeSecrets{}).send(context.Background(), channel, testNotification()); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+
+	var payload map[string]string
+	if err := json.Unmarshal((<-requests).body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	want := "[FIRING] Task \"nightly\" has failed 3 times in a row (HTTP 503)"
+	if payload["text"] != want {
+		t.Errorf("text = %q, want %q", payload["text"], want)
+	}
+}
+
+func TestSendBlockedByEgress(t *testing.T) {
+	server, _ := captureServer(t, http.StatusOK)
+	egress, err := scheduler.NewEgressPolicy(config.EgressConfig{BlockPrivate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secretURL := server.URL + "/hooks/T000/B000/s3cr3t-token"
+	alerter := NewAlerter(nil, fakeSecrets{"webhook-url": secretURL}, egress.DialContext, config.AlertingConfig{Timeout: 5 * time.Second})
+	channel := &models.NotificationChannel{Name: "ops", Type: models.ChannelWebhook, Config: models.ChannelConfig{URLSecretName: "webhook-url"}}
+
+	_, err = alerter.send(context.Background(), channel, testNotification())
+	if !errors.Is(err, scheduler.ErrEgressBlocked) {
+		t.Fatalf("send() error = %v, want %v", err, scheduler.ErrEgressBlocked)
+	}
+	if strings.Contains(err.Error(), "s3cr3t-token") {
+		t.Errorf("send() error = %q, includes the channel's secret URL", err)
+	}
+}
+
+// smtpSession is what the fake SMTP server received.
+type smtpSession struct {
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// fakeSMTPServer accepts one session, advertising AUTH PLAIN, and sends what
+// it received on the returned channel.
+func fakeSMTPServer(t *testing.T) (string, int, <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var session smtpSession
+		defer func() { sessions <- session }()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case verb == "EHLO" || verb == "HELO":
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case verb == "AUTH":
+				session.auth = strings.TrimPrefix(line, "AUTH PLAIN ")
+				reply("235 2.7.0 Authentication successful")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				session.from = line[len("MAIL FROM:"):]
+				reply("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				session.rcpt = append(session.rcpt, line[len("RCPT TO:"):])
+				reply("250 OK")
+			case verb == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				session.data = data.String()
+				reply("250 OK")
+			case verb == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, sessions
+}
+
+func TestSendEmail(t *testing.T) {
+	host, port, sessions := fakeSMTPServer(t)
+	channel := &models.NotificationChannel{
+		Name: "ops-email",
+		Type: models.ChannelEmail,
+		Config: models.ChannelConfig{
+			SMTPHost:           host,
+			SMTPPort:           port,
+			Username:           "alerts",
+			PasswordSecretName: "smtp-password",
+			From:               "Scheduler <alerts@example.com>",
+			To:                 []string{"Ops <ops@example.com>", "oncall@example.com"},
+		},
+	}
+
+	alerter := newTestAlerter(t, fakeSecrets{"smtp-password": "hunter2"})
+	if _, err := alerter.send(context.Background(), channel, testNotification()); err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+
+	session := <-sessions
+	if want := base64.StdEncoding.EncodeToString([]byte("\x00alerts\x00hunter2")); session.auth != want {
+		t.Errorf("AUTH = %q, want %q", session.auth, want)
+	}
+	if session.from != "<alerts@example.com>" {
+		t.Errorf("MAIL FROM = %q, want <alerts@example.com>", session.from)
+	}
+	if want := []string{"<ops@example.com>", "<oncall@example.com>"}; strings.Join(session.rcpt, ",") != strings.Join(want, ",") {
+		t.Errorf("RCPT TO = %v, want %v", session.rcpt, want)
+	}
+	for _, want := range []string{
+		"Subject: [FIRING] Task \"nightly\" has failed 3 times in a row (HTTP 503)\r\n",
+		"To: Ops <ops@example.com>, oncall@example.com\r\n",
+		"Task: nightly (",
+		"Status: 503\r\n",
+	} {
+		if !strings.Contains(session.data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, session.data)
+		}
+	}
+}
+
+func TestMailBodySanitizesSubject(t *testing.T) {
+	notification := testNotification()
+	notification.Summary = "line one\r\nBcc: attacker@example.com"
+
+	body := string(mailBody(models.ChannelConfig{From: "a@example.com", To: []string{"b@example.com"}}, notification))
+	headers, _, _ := strings.Cut(body, "\r\n\r\n")
+	if strings.Contains(headers, "\r\nBcc:") {
+		t.Errorf("subject injected a header:\n%s", headers)
+	}
+}
@@ -0,0 +1,143 @@
+package alerting
+
+import (
+	"context"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+)
+
+// Watchdog is a dead man's switch for tasks with a heartbeat policy. It
+// flags tasks that have not run by their expected time plus grace period as
+// overdue and raises their overdue alert rule. Check-in monitors are marked
+// late once a ping is due and down once the grace period has passed. It runs
+// on its own ticker, so it keeps working when the cron runner stalls.
+type Watchdog struct {
+	repo     *db.Repository
+	alerter  *Alerter
+	interval time.Duration
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+func NewWatchdog(repo *db.Repository, alerter *Alerter, interval time.Duration) *Watchdog {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Watchdog{
+		repo:     repo,
+		alerter:  alerter,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+}
+
+// Start checks every configured interval.
+func (w *Watchdog) Start() {
+	go func() {
+		defer close(w.doneCh)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.Run()
+			case <-w.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop, waiting for an in-progress check to finish.
+func (w *Watchdog) Stop() {
+	close(w.stopCh)
+	<-w.doneCh
+}
+
+// Run checks every task with a heartbeat policy once.
+func (w *Watchdog) Run() {
+	ctx, span := tracing.Start(context.Background(), "Watchdog.Run")
+	defer span.End()
+	repo := w.repo.WithContext(ctx)
+
+	tasks, err := repo.GetHeartbeatTasks()
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to list tasks with a heartbeat policy", "error", err)
+		return
+	}
+
+	if len(tasks) == 0 {
+		return
+	}
+	ids := make([]uuid.UUID, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	lastRuns, err := repo.GetLastRunTimes(ids)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get last run times", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for i := range tasks {
+		task := &tasks[i]
+		var lastRun *time.Time
+		if at, ok := lastRuns[task.ID]; ok {
+			lastRun = &at
+		}
+
+		expectedBy, ok := scheduler.ExpectedRun(task, lastRun)
+		deadline := expectedBy.Add(time.Duration(task.Heartbeat.GraceSeconds) * time.Second)
+		overdue := ok && now.After(deadline)
+
+		switch {
+		case overdue && !task.Overdue:
+			logger.WarnContext(ctx, "Task is overdue", "task_id", task.ID, "task_name", task.Name, "expected_by", expectedBy, "last_run_at", lastRun)
+			if err := repo.SetTaskOverdue(task.ID, deadline); err != nil {
+				logger.ErrorContext(ctx, "Failed to flag task as overdue", "task_id", task.ID, "error", err)
+			}
+		case !overdue && task.Overdue:
+			if err := repo.ClearTaskOverdue(task.ID); err != nil {
+				logger.ErrorContext(ctx, "Failed to clear overdue flag", "task_id", task.ID, "error", err)
+			}
+		}
+
+		if task.Checkin != nil {
+			w.markCheckin(ctx, task, overdue, ok && now.After(expectedBy))
+		}
+
+		w.alerter.ObserveOverdue(ctx, task, lastRun, expectedBy, overdue)
+	}
+}
+
+// markCheckin moves a check-in monitor to down when it is overdue, or to late
+// when a ping is due. Monitors only return to up when pinged.
+func (w *Watchdog) markCheckin(ctx context.Context, task *models.Task, overdue, due bool) {
+	var status models.CheckinStatus
+	switch {
+	case overdue:
+		status = models.CheckinDown
+	case due && (task.Checkin.Status == models.CheckinNew || task.Checkin.Status == models.CheckinUp):
+		status = models.CheckinLate
+	default:
+		return
+	}
+
+	changed, err := w.repo.WithContext(ctx).MarkCheckin(task.ID, status, task.Checkin.LastPingAt)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to update check-in status", "task_id", task.ID, "error", err)
+		return
+	}
+	if changed {
+		logger.InfoContext(ctx, "Check-in status changed", "task_id", task.ID, "task_name", task.Name, "status", status)
+	}
+}
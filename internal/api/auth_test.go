@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ayushsarode/task-scheduler/internal/audit"
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fakeKeys authenticates the keys it holds, by value.
+type fakeKeys struct {
+	disabled bool
+	keys     map[string]*models.APIKey
+	err      error
+	used     []string
+}
+
+func (f *fakeKeys) Enabled() bool { return !f.disabled }
+
+func (f *fakeKeys) Authenticate(ctx context.Context, value string) (*models.APIKey, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	key, ok := f.keys[value]
+	if !ok {
+		return nil, errors.New("invalid api key")
+	}
+	return key, nil
+}
+
+func (f *fakeKeys) MarkUsed(ctx context.Context, key *models.APIKey) error {
+	f.used = append(f.used, key.Name)
+	return nil
+}
+
+// serveScoped sends r through requireScope and returns the response and the
+// actor the handler saw, if it was reached.
+func serveScoped(keys keyAuthenticator, scope models.APIScope, r *http.Request) (*httptest.ResponseRecorder, *audit.Actor) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	var actor *audit.Actor
+	router.GET("/", requireScope(keys, scope), func(c *gin.Context) {
+		a := audit.ActorFrom(c.Request.Context())
+		actor = &a
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w, actor
+}
+
+func TestRequireScope(t *testing.T) {
+	keys := map[string]*models.APIKey{
+		"reader-key": {ID: uuid.New(), Name: "reader", Scopes: models.APIScopes{models.ScopeTasksRead}},
+		"writer-key": {ID: uuid.New(), Name: "writer", Scopes: models.APIScopes{models.ScopeTasksRead, models.ScopeTasksWrite}},
+		"admin-key":  {ID: uuid.New(), Name: "admin", Scopes: models.APIScopes{models.ScopeAdmin}},
+	}
+
+	tests := []struct {
+		name      string
+		keys      *fakeKeys
+		header    string
+		value     string
+		want      int
+		wantActor string
+	}{
+		{name: "authentication disabled", keys: &fakeKeys{disabled: true}, want: http.StatusNoContent, wantActor: "system"},
+		{name: "no key", keys: &fakeKeys{keys: keys}, want: http.StatusUnauthorized},
+		{name: "unknown key", keys: &fakeKeys{keys: keys}, header: "Authorization", value: "Bearer other-key", want: http.StatusUnauthorized},
+		{name: "expired key", keys: &fakeKeys{err: errors.New("api key expired")}, header: "Authorization", value: "Bearer reader-key", want: http.StatusUnauthorized},
+		{name: "lookup fails", keys: &fakeKeys{err: errors.New("connection refused")}, header: "Authorization", value: "Bearer reader-key", want: http.StatusInternalServerError},
+		{name: "key lacks the scope", keys: &fakeKeys{keys: keys}, header: "Authorization", value: "Bearer reader-key", want: http.StatusForbidden},
+		{name: "bearer key with the scope", keys: &fakeKeys{keys: keys}, header: "Authorization", value: "Bearer writer-key", want: http.StatusNoContent, wantActor: "writer"},
+		{name: "X-API-Key with the scope", keys: &fakeKeys{keys: keys}, header: APIKeyHeader, value: "writer-key", want: http.StatusNoContent, wantActor: "writer"},
+		{name: "admin key", keys: &fakeKeys{keys: keys}, header: "Authorization", value: "Bearer admin-key", want: http.StatusNoContent, wantActor: "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set(tt.header, tt.value)
+			}
+			r.Header.Set(audit.ActorHeader, "spoofed")
+
+			w, actor := serveScoped(tt.keys, models.ScopeTasksWrite, r)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if tt.want == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q, want Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+
+			if tt.wantActor == "" {
+				if actor != nil {
+					t.Errorf("handler reached as %q, want the request rejected", actor.Name)
+				}
+				if len(tt.keys.used) > 0 {
+					t.Errorf("MarkUsed() called for %v on a rejected request", tt.keys.used)
+				}
+				return
+			}
+			if actor == nil || actor.Name != tt.wantActor {
+				t.Fatalf("actor = %+v, want %q", actor, tt.wantActor)
+			}
+			if tt.keys.disabled {
+				return
+			}
+			if !actor.Allows(models.ScopeTasksWrite) || actor.Allows(models.ScopeAdmin) != (tt.wantActor == "admin") {
+				t.Errorf("actor scopes = %v, want the key's scopes", actor.Scopes)
+			}
+			if len(tt.keys.used) != 1 || tt.keys.used[0] != tt.wantActor {
+				t.Errorf("MarkUsed() calls = %v, want one for %q", tt.keys.used, tt.wantActor)
+			}
+		})
+	}
+}
+
+func TestRequireScopeBootstrapKey(t *testing.T) {
+	// The bootstrap key is checked before the stored keys, so no repository
+	// is needed
+	keys := services.NewAPIKeyService(nil, config.AuthConfig{Enabled: true, BootstrapKey: "bootstrap-secret"})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer bootstrap-secret")
+	w, actor := serveScoped(keys, models.ScopeAdmin, r)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNoContent, w.Body)
+	}
+	if actor == nil || actor.Name != "bootstrap" || !actor.Allows(models.ScopeAdmin) {
+		t.Errorf("actor = %+v, want the bootstrap admin", actor)
+	}
+}
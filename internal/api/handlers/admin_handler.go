@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+func (h *AdminHandler) ListCircuitBreakers(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, h.adminService.ListCircuitBreakers(c.Request.Context()))
+}
+
+func (h *AdminHandler) ResetCircuitBreaker(c *gin.Context) {
+	host := c.Param("host")
+	if !h.adminService.ResetCircuitBreaker(c.Request.Context(), host) {
+		utils.NotFoundResponse(c, "No circuit breaker for host "+host)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Circuit breaker reset successfully"})
+}
+
+func (h *AdminHandler) GetLogLevels(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, h.adminService.GetLogLevels(c.Request.Context()))
+}
+
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req models.SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	levels, err := h.adminService.SetLogLevel(c.Request.Context(), req)
+	if err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, levels)
+}
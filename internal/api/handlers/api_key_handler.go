@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateKey returns the new key's value, which can't be retrieved later.
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	var req models.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	key, err := h.apiKeyService.CreateKey(c.Request.Context(), req)
+	if err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, key)
+}
+
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) GetKey(c *gin.Context) {
+	key, err := h.apiKeyService.GetKey(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, key)
+}
+
+func (h *APIKeyHandler) UpdateKey(c *gin.Context) {
+	var req models.UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	key, err := h.apiKeyService.UpdateKey(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, key)
+}
+
+func (h *APIKeyHandler) DeleteKey(c *gin.Context) {
+	if err := h.apiKeyService.DeleteKey(c.Request.Context(), c.Param("name")); err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
+
+func apiKeyErrorResponse(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "api key not found":
+		utils.NotFoundResponse(c, "API key not found")
+	case err.Error() == "api key already exists":
+		utils.ErrorResponse(c, http.StatusConflict, "API key already exists")
+	case err.Error() == "expires_at must be in the future", strings.HasSuffix(err.Error(), "is reserved"):
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	default:
+		utils.InternalErrorResponse(c, err.Error())
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	var params models.ListAuditParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Set defaults
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	events, total, err := h.auditService.ListEvents(c.Request.Context(), params)
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.Limit, total)
+	utils.SuccessResponseWithMeta(c, http.StatusOK, events, meta)
+}
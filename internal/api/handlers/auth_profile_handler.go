@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type AuthProfileHandler struct {
+	authProfileService *services.AuthProfileService
+}
+
+func NewAuthProfileHandler(authProfileService *services.AuthProfileService) *AuthProfileHandler {
+	return &AuthProfileHandler{
+		authProfileService: authProfileService,
+	}
+}
+
+func (h *AuthProfileHandler) CreateAuthProfile(c *gin.Context) {
+	var req models.CreateAuthProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	profile, err := h.authProfileService.CreateAuthProfile(c.Request.Context(), req)
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, profile)
+}
+
+func (h *AuthProfileHandler) ListAuthProfiles(c *gin.Context) {
+	profiles, err := h.authProfileService.ListAuthProfiles(c.Request.Context())
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, profiles)
+}
+
+func (h *AuthProfileHandler) GetAuthProfile(c *gin.Context) {
+	profile, err := h.authProfileService.GetAuthProfile(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		utils.NotFoundResponse(c, "Auth profile not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, profile)
+}
+
+func (h *AuthProfileHandler) UpdateAuthProfile(c *gin.Context) {
+	var req models.UpdateAuthProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	profile, err := h.authProfileService.UpdateAuthProfile(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, profile)
+}
+
+func (h *AuthProfileHandler) DeleteAuthProfile(c *gin.Context) {
+	if err := h.authProfileService.DeleteAuthProfile(c.Request.Context(), c.Param("name")); err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Auth profile deleted successfully"})
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ChannelHandler struct {
+	channelService *services.ChannelService
+}
+
+func NewChannelHandler(channelService *services.ChannelService) *ChannelHandler {
+	return &ChannelHandler{
+		channelService: channelService,
+	}
+}
+
+func (h *ChannelHandler) CreateChannel(c *gin.Context) {
+	var req models.CreateChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	channel, err := h.channelService.CreateChannel(c.Request.Context(), req)
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, channel)
+}
+
+func (h *ChannelHandler) ListChannels(c *gin.Context) {
+	channels, err := h.channelService.ListChannels(c.Request.Context())
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, channels)
+}
+
+func (h *ChannelHandler) GetChannel(c *gin.Context) {
+	channel, err := h.channelService.GetChannel(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		utils.NotFoundResponse(c, "Channel not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, channel)
+}
+
+func (h *ChannelHandler) UpdateChannel(c *gin.Context) {
+	var req models.UpdateChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	channel, err := h.channelService.UpdateChannel(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, channel)
+}
+
+func (h *ChannelHandler) DeleteChannel(c *gin.Context) {
+	if err := h.channelService.DeleteChannel(c.Request.Context(), c.Param("name")); err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Channel deleted successfully"})
+}
+
+// TestChannel sends a test notification and returns every delivery attempt.
+// A failed delivery is reported as 502 with the attempts as data.
+func (h *ChannelHandler) TestChannel(c *gin.Context) {
+	deliveries, err := h.channelService.TestChannel(c.Request.Context(), c.Param("name"))
+	if err != nil && deliveries == nil {
+		utils.NotFoundResponse(c, "Channel not found")
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, utils.Response{
+			Success: false,
+			Data:    deliveries,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, deliveries)
+}
+
+func (h *ChannelHandler) ListDeliveries(c *gin.Context) {
+	var params models.ListDeliveriesParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Set defaults
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	deliveries, total, err := h.channelService.ListDeliveries(c.Request.Context(), c.Param("name"), params)
+	if err != nil {
+		utils.NotFoundResponse(c, "Channel not found")
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.Limit, total)
+	utils.SuccessResponseWithMeta(c, http.StatusOK, deliveries, meta)
+}
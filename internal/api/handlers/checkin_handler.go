@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// maxPingBody is how much of a ping's body, such as a job's log tail, is stored.
+const maxPingBody = 10 * 1024
+
+type CheckinHandler struct {
+	checkinService *services.CheckinService
+}
+
+func NewCheckinHandler(checkinService *services.CheckinService) *CheckinHandler {
+	return &CheckinHandler{
+		checkinService: checkinService,
+	}
+}
+
+func (h *CheckinHandler) Ping(c *gin.Context) {
+	h.ping(c, models.PingSuccess)
+}
+
+func (h *CheckinHandler) PingStart(c *gin.Context) {
+	h.ping(c, models.PingStart)
+}
+
+func (h *CheckinHandler) PingFail(c *gin.Context) {
+	h.ping(c, models.PingFail)
+}
+
+func (h *CheckinHandler) ping(c *gin.Context, kind models.PingKind) {
+	ping := models.Ping{
+		Kind:      kind,
+		Method:    c.Request.Method,
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPingBody+1))
+		if err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+		ping.BodySize = int64(len(body))
+		if c.Request.ContentLength > ping.BodySize {
+			ping.BodySize = c.Request.ContentLength
+		}
+		if len(body) > maxPingBody {
+			body = body[:maxPingBody]
+			ping.Truncated = true
+		}
+		ping.Body = string(body)
+	}
+
+	checkin, err := h.checkinService.Ping(c.Request.Context(), c.Param("token"), ping)
+	if err != nil {
+		if err.Error() == "check-in not found" {
+			utils.NotFoundResponse(c, "Check-in not found")
+			return
+		}
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, checkin)
+}
@@ -1,22 +1,41 @@
 package handlers
 
 import (
-"net/http"
+	"net/http"
 
-"github.com/gin-gonic/gin"
-"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
 )
 
-type HealthHandler struct{}
+type HealthHandler struct {
+	healthService *services.HealthService
+}
+
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+// Livez reports whether the process is up. It does not check dependencies,
+// so a failing database does not get the process restarted.
+func (h *HealthHandler) Livez(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, h.healthService.Live())
 }
 
-func (h *HealthHandler) HealthCheck(c *gin.Context) {
-	utils.SuccessResponse(c, http.StatusOK, gin.H{
-"status":  "healthy",
-"service": "task-scheduler",
-"version": "1.0.0",
-})
+// Readyz runs the readiness checks and responds 503 if any of them fail.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.healthService.Ready(c.Request.Context())
+	if report.Status != models.CheckOK {
+		c.JSON(http.StatusServiceUnavailable, utils.Response{
+			Success: false,
+			Data:    report,
+			Error:   "Service is not ready",
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, report)
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 "net/http"
+"time"
 
 "github.com/gin-gonic/gin"
 "github.com/google/uuid"
@@ -42,12 +43,65 @@ func (h *ResultHandler) ListResults(c *gin.Context) {
 		}
 	}
 
-	results, total, err := h.resultService.ListAllResults(params)
+	if params.Cursor != "" {
+		after, err := models.ParseResultCursor(params.Cursor)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+		params.After = &after
+	}
+
+	results, total, err := h.resultService.ListAllResults(c.Request.Context(), params)
 	if err != nil {
 		utils.InternalErrorResponse(c, err.Error())
 		return
 	}
 
+	// A full page may be followed by more results
+	var nextCursor string
+	if len(results) == params.Limit {
+		last := results[len(results)-1]
+		nextCursor = models.ResultCursor{RunAt: last.RunAt, ID: last.ID}.String()
+	}
+
+	if params.After != nil {
+		utils.SuccessResponseWithMeta(c, http.StatusOK, results, utils.CursorMeta{Limit: params.Limit, NextCursor: nextCursor})
+		return
+	}
 	meta := utils.CalculatePaginationMeta(params.Page, params.Limit, total)
+	meta.NextCursor = nextCursor
 	utils.SuccessResponseWithMeta(c, http.StatusOK, results, meta)
 }
+
+func (h *ResultHandler) GetResultBody(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid result ID")
+		return
+	}
+
+	// The result's run_at narrows the lookup to its partition
+	var runAt *time.Time
+	if value := c.Query("run_at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid run_at")
+			return
+		}
+		runAt = &parsed
+	}
+
+	body, contentType, err := h.resultService.OpenResultBody(c.Request.Context(), id, runAt)
+	if err != nil {
+		if err.Error() == "task result not found" {
+			utils.NotFoundResponse(c, "Result not found")
+			return
+		}
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+	defer body.Close()
+
+	c.DataFromReader(http.StatusOK, -1, contentType, body, nil)
+}
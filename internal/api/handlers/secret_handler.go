@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/secrets"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type SecretHandler struct {
+	secretService *services.SecretService
+}
+
+func NewSecretHandler(secretService *services.SecretService) *SecretHandler {
+	return &SecretHandler{
+		secretService: secretService,
+	}
+}
+
+func (h *SecretHandler) CreateSecret(c *gin.Context) {
+	var req models.CreateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	secret, err := h.secretService.CreateSecret(c.Request.Context(), req)
+	if err != nil {
+		secretErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, secret)
+}
+
+func (h *SecretHandler) ListSecrets(c *gin.Context) {
+	secrets, err := h.secretService.ListSecrets(c.Request.Context())
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, secrets)
+}
+
+func (h *SecretHandler) UpdateSecret(c *gin.Context) {
+	var req models.UpdateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	secret, err := h.secretService.UpdateSecret(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		secretErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, secret)
+}
+
+func (h *SecretHandler) DeleteSecret(c *gin.Context) {
+	if err := h.secretService.DeleteSecret(c.Request.Context(), c.Param("name")); err != nil {
+		secretErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "Secret deleted successfully"})
+}
+
+func (h *SecretHandler) RotateKeys(c *gin.Context) {
+	rotated, err := h.secretService.RotateKeys(c.Request.Context())
+	if err != nil {
+		secretErrorResponse(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"rotated": rotated})
+}
+
+func secretErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, secrets.ErrNotConfigured):
+		utils.ErrorResponse(c, http.StatusServiceUnavailable, err.Error())
+	case err.Error() == "secret not found":
+		utils.NotFoundResponse(c, "Secret not found")
+	default:
+		utils.InternalErrorResponse(c, err.Error())
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultStatsRange    = 24 * time.Hour
+	defaultStatsInterval = time.Hour
+	maxStatsBuckets      = 1000
+)
+
+type StatsHandler struct {
+	resultService *services.ResultService
+}
+
+func NewStatsHandler(resultService *services.ResultService) *StatsHandler {
+	return &StatsHandler{
+		resultService: resultService,
+	}
+}
+
+func (h *StatsHandler) GetTaskStats(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+	h.respond(c, &id)
+}
+
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	h.respond(c, nil)
+}
+
+func (h *StatsHandler) respond(c *gin.Context, taskID *uuid.UUID) {
+	var params models.StatsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	from, to, interval, err := statsRange(params)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.resultService.GetStats(c.Request.Context(), taskID, from, to, interval)
+	if err != nil {
+		if err.Error() == "task not found" {
+			utils.NotFoundResponse(c, "Task not found")
+			return
+		}
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, stats)
+}
+
+// statsRange applies defaults to the requested range and interval: the last
+// 24 hours in one-hour buckets. Runs are counted per hour, so from is rounded
+// down and to rounded up to the hour, and interval must be whole hours.
+func statsRange(params models.StatsParams) (time.Time, time.Time, time.Duration, error) {
+	to := time.Now().UTC()
+	if params.To != nil {
+		to = params.To.UTC()
+	}
+	from := to.Add(-defaultStatsRange)
+	if params.From != nil {
+		from = params.From.UTC()
+	}
+	from = from.Truncate(time.Hour)
+	if rounded := to.Truncate(time.Hour); rounded.Before(to) {
+		to = rounded.Add(time.Hour)
+	}
+	if !from.Before(to) {
+		return from, to, 0, fmt.Errorf("from must be before to")
+	}
+
+	interval := defaultStatsInterval
+	if params.Interval != "" {
+		var err error
+		if interval, err = time.ParseDuration(params.Interval); err != nil {
+			return from, to, 0, fmt.Errorf("invalid interval: %v", err)
+		}
+		if interval < time.Hour || interval%time.Hour != 0 {
+			return from, to, 0, fmt.Errorf("interval must be a whole number of hours")
+		}
+	}
+	if buckets := (to.Sub(from) + interval - 1) / interval; buckets > maxStatsBuckets {
+		return from, to, 0, fmt.Errorf("range and interval produce more than %d buckets", maxStatsBuckets)
+	}
+
+	return from, to, interval, nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+"errors"
 "net/http"
 "strconv"
 
@@ -28,8 +29,12 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskService.CreateTask(req)
+	task, err := h.taskService.CreateTask(c.Request.Context(), req)
 	if err != nil {
+		if errors.Is(err, services.ErrSecretAccessDenied) {
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+			return
+		}
 		utils.InternalErrorResponse(c, err.Error())
 		return
 	}
@@ -52,7 +57,7 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 		params.Limit = 10
 	}
 
-	tasks, total, err := h.taskService.ListTasks(params)
+	tasks, total, err := h.taskService.ListTasks(c.Request.Context(), params)
 	if err != nil {
 		utils.InternalErrorResponse(c, err.Error())
 		return
@@ -70,7 +75,7 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskService.GetTask(id)
+	task, err := h.taskService.GetTask(c.Request.Context(), id)
 	if err != nil {
 		utils.NotFoundResponse(c, "Task not found")
 		return
@@ -93,9 +98,18 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskService.UpdateTask(id, req)
+	task, err := h.taskService.UpdateTask(c.Request.Context(), id, req)
 	if err != nil {
-		utils.InternalErrorResponse(c, err.Error())
+		switch {
+		case errors.Is(err, services.ErrSecretAccessDenied):
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		case err.Error() == "task not found":
+			utils.NotFoundResponse(c, "Task not found")
+		case err.Error() == "task version conflict":
+			utils.ErrorResponse(c, http.StatusConflict, "Task was changed by another request; reload it and try again")
+		default:
+			utils.InternalErrorResponse(c, err.Error())
+		}
 		return
 	}
 
@@ -110,7 +124,7 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.taskService.DeleteTask(id); err != nil {
+	if err := h.taskService.DeleteTask(c.Request.Context(), id); err != nil {
 		utils.InternalErrorResponse(c, err.Error())
 		return
 	}
@@ -143,8 +157,8 @@ func (h *TaskHandler) GetTaskResults(c *gin.Context) {
 	}
 
 	// Get task results from result service
-	resultService := services.NewResultService(h.taskService.GetRepository())
-	results, total, err := resultService.GetTaskResults(taskID, page, limit)
+	resultService := services.NewResultService(h.taskService.GetRepository(), nil)
+	results, total, err := resultService.GetTaskResults(c.Request.Context(), taskID, page, limit)
 	if err != nil {
 		utils.InternalErrorResponse(c, err.Error())
 		return
@@ -153,3 +167,77 @@ func (h *TaskHandler) GetTaskResults(c *gin.Context) {
 	meta := utils.CalculatePaginationMeta(page, limit, total)
 	utils.SuccessResponseWithMeta(c, http.StatusOK, results, meta)
 }
+
+// ListVersions returns a page of a task's versions, newest first, each with
+// its changes from the version before it.
+func (h *TaskHandler) ListVersions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var params models.ListVersionsParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Set defaults
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	versions, total, err := h.taskService.ListVersions(c.Request.Context(), id, params)
+	if err != nil {
+		if err.Error() == "task not found" {
+			utils.NotFoundResponse(c, "Task not found")
+			return
+		}
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.Limit, total)
+	utils.SuccessResponseWithMeta(c, http.StatusOK, versions, meta)
+}
+
+// RestoreVersion rolls a task back to the trigger and action of an earlier
+// version.
+func (h *TaskHandler) RestoreVersion(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("n"))
+	if err != nil || number < 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid version number")
+		return
+	}
+
+	task, err := h.taskService.RestoreVersion(c.Request.Context(), id, number)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSecretAccessDenied):
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		case err.Error() == "task not found":
+			utils.NotFoundResponse(c, "Task not found")
+		case err.Error() == "task version not found":
+			utils.NotFoundResponse(c, "Task version not found")
+		case err.Error() == "task version conflict":
+			utils.ErrorResponse(c, http.StatusConflict, "Task was changed by another request; reload it and try again")
+		default:
+			utils.InternalErrorResponse(c, err.Error())
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, task)
+}
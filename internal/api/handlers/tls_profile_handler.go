@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/services"
+	"github.com/ayushsarode/task-scheduler/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TLSProfileHandler struct {
+	tlsProfileService *services.TLSProfileService
+}
+
+func NewTLSProfileHandler(tlsProfileService *services.TLSProfileService) *TLSProfileHandler {
+	return &TLSProfileHandler{
+		tlsProfileService: tlsProfileService,
+	}
+}
+
+func (h *TLSProfileHandler) CreateTLSProfile(c *gin.Context) {
+	var req models.CreateTLSProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	profile, err := h.tlsProfileService.CreateTLSProfile(c.Request.Context(), req)
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, profile)
+}
+
+func (h *TLSProfileHandler) ListTLSProfiles(c *gin.Context) {
+	profiles, err := h.tlsProfileService.ListTLSProfiles(c.Request.Context())
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, profiles)
+}
+
+func (h *TLSProfileHandler) GetTLSProfile(c *gin.Context) {
+	profile, err := h.tlsProfileService.GetTLSProfile(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		utils.NotFoundResponse(c, "TLS profile not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, profile)
+}
+
+func (h *TLSProfileHandler) UpdateTLSProfile(c *gin.Context) {
+	var req models.UpdateTLSProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	profile, err := h.tlsProfileService.UpdateTLSProfile(c.Request.Context(), c.Param("name"), req)
+	if err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, profile)
+}
+
+func (h *TLSProfileHandler) DeleteTLSProfile(c *gin.Context) {
+	if err := h.tlsProfileService.DeleteTLSProfile(c.Request.Context(), c.Param("name")); err != nil {
+		utils.InternalErrorResponse(c, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, gin.H{"message": "TLS profile deleted successfully"})
+}
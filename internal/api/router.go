@@ -1,30 +1,133 @@
 package api
 
 import (
+"net/http"
+
 "github.com/gin-gonic/gin"
 "github.com/ayushsarode/task-scheduler/internal/api/handlers"
+"github.com/ayushsarode/task-scheduler/internal/metrics"
+"github.com/ayushsarode/task-scheduler/internal/models"
 "github.com/ayushsarode/task-scheduler/internal/services"
 )
 
-func SetupRoutes(router *gin.Engine, taskService *services.TaskService, resultService *services.ResultService) {
-	// Health check
-	healthHandler := handlers.NewHealthHandler()
-	router.GET("/health", healthHandler.HealthCheck)
+// Services bundles the services exposed through the HTTP API.
+type Services struct {
+	Tasks        *services.TaskService
+	Results      *services.ResultService
+	Secrets      *services.SecretService
+	AuthProfiles *services.AuthProfileService
+	TLSProfiles  *services.TLSProfileService
+	Channels     *services.ChannelService
+	Checkins     *services.CheckinService
+	Audit        *services.AuditService
+	APIKeys      *services.APIKeyService
+	Admin        *services.AdminService
+	Health       *services.HealthService
+}
+
+func SetupRoutes(router *gin.Engine, svc Services) {
+	router.Use(metrics.GinMiddleware())
+
+	// Prometheus metrics; they reveal task IDs and routes, so an admin key
+	// is required when authentication is enabled
+	router.GET("/metrics", requireScope(svc.APIKeys, models.ScopeAdmin), gin.WrapH(metrics.Handler()))
 
-	// API v1 routes
+	// Health checks; /health is kept as an alias of /readyz
+	healthHandler := handlers.NewHealthHandler(svc.Health)
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/health", healthHandler.Readyz)
+
+	// API v1 routes. Each group requires an API key with its scope when
+	// authentication is enabled.
 	v1 := router.Group("/api/v1")
+	tasksRead := v1.Group("", requireScope(svc.APIKeys, models.ScopeTasksRead))
+	tasksWrite := v1.Group("", requireScope(svc.APIKeys, models.ScopeTasksWrite))
+	resultsRead := v1.Group("", requireScope(svc.APIKeys, models.ScopeResultsRead))
+	adminOnly := v1.Group("", requireScope(svc.APIKeys, models.ScopeAdmin))
 	{
 		// Task handlers
-		taskHandler := handlers.NewTaskHandler(taskService)
-		v1.POST("/tasks", taskHandler.CreateTask)
-		v1.GET("/tasks", taskHandler.ListTasks)
-		v1.GET("/tasks/:id", taskHandler.GetTask)
-		v1.PUT("/tasks/:id", taskHandler.UpdateTask)
-		v1.DELETE("/tasks/:id", taskHandler.DeleteTask)
-		v1.GET("/tasks/:id/results", taskHandler.GetTaskResults)
+		taskHandler := handlers.NewTaskHandler(svc.Tasks)
+		tasksWrite.POST("/tasks", taskHandler.CreateTask)
+		tasksRead.GET("/tasks", taskHandler.ListTasks)
+		tasksRead.GET("/tasks/:id", taskHandler.GetTask)
+		tasksWrite.PUT("/tasks/:id", taskHandler.UpdateTask)
+		tasksWrite.DELETE("/tasks/:id", taskHandler.DeleteTask)
+		resultsRead.GET("/tasks/:id/results", taskHandler.GetTaskResults)
+		tasksRead.GET("/tasks/:id/versions", taskHandler.ListVersions)
+		tasksWrite.POST("/tasks/:id/versions/:n/restore", taskHandler.RestoreVersion)
+
+		// Check-in ping handlers; the token in the URL identifies the monitor,
+		// so they take no API key
+		checkinHandler := handlers.NewCheckinHandler(svc.Checkins)
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			v1.Handle(method, "/ping/:token", checkinHandler.Ping)
+			v1.Handle(method, "/ping/:token/start", checkinHandler.PingStart)
+			v1.Handle(method, "/ping/:token/fail", checkinHandler.PingFail)
+		}
 
 		// Result handlers
-		resultHandler := handlers.NewResultHandler(resultService)
-		v1.GET("/results", resultHandler.ListResults)
+		resultHandler := handlers.NewResultHandler(svc.Results)
+		resultsRead.GET("/results", resultHandler.ListResults)
+		resultsRead.GET("/results/:id/body", resultHandler.GetResultBody)
+
+		// Stats handlers
+		statsHandler := handlers.NewStatsHandler(svc.Results)
+		resultsRead.GET("/tasks/:id/stats", statsHandler.GetTaskStats)
+		resultsRead.GET("/stats", statsHandler.GetStats)
+
+		// Secret handlers (values are write-only)
+		secretHandler := handlers.NewSecretHandler(svc.Secrets)
+		adminOnly.POST("/secrets", secretHandler.CreateSecret)
+		adminOnly.GET("/secrets", secretHandler.ListSecrets)
+		adminOnly.PUT("/secrets/:name", secretHandler.UpdateSecret)
+		adminOnly.DELETE("/secrets/:name", secretHandler.DeleteSecret)
+		adminOnly.POST("/secrets/rotate", secretHandler.RotateKeys)
+
+		// Auth profile handlers
+		authProfileHandler := handlers.NewAuthProfileHandler(svc.AuthProfiles)
+		adminOnly.POST("/auth-profiles", authProfileHandler.CreateAuthProfile)
+		adminOnly.GET("/auth-profiles", authProfileHandler.ListAuthProfiles)
+		adminOnly.GET("/auth-profiles/:name", authProfileHandler.GetAuthProfile)
+		adminOnly.PUT("/auth-profiles/:name", authProfileHandler.UpdateAuthProfile)
+		adminOnly.DELETE("/auth-profiles/:name", authProfileHandler.DeleteAuthProfile)
+
+		// TLS profile handlers
+		tlsProfileHandler := handlers.NewTLSProfileHandler(svc.TLSProfiles)
+		adminOnly.POST("/tls-profiles", tlsProfileHandler.CreateTLSProfile)
+		adminOnly.GET("/tls-profiles", tlsProfileHandler.ListTLSProfiles)
+		adminOnly.GET("/tls-profiles/:name", tlsProfileHandler.GetTLSProfile)
+		adminOnly.PUT("/tls-profiles/:name", tlsProfileHandler.UpdateTLSProfile)
+		adminOnly.DELETE("/tls-profiles/:name", tlsProfileHandler.DeleteTLSProfile)
+
+		// Notification channel handlers
+		channelHandler := handlers.NewChannelHandler(svc.Channels)
+		adminOnly.POST("/channels", channelHandler.CreateChannel)
+		adminOnly.GET("/channels", channelHandler.ListChannels)
+		adminOnly.GET("/channels/:name", channelHandler.GetChannel)
+		adminOnly.PUT("/channels/:name", channelHandler.UpdateChannel)
+		adminOnly.DELETE("/channels/:name", channelHandler.DeleteChannel)
+		adminOnly.POST("/channels/:name/test", channelHandler.TestChannel)
+		adminOnly.GET("/channels/:name/deliveries", channelHandler.ListDeliveries)
+
+		// Audit log handlers
+		auditHandler := handlers.NewAuditHandler(svc.Audit)
+		adminOnly.GET("/audit", auditHandler.ListEvents)
+
+		// Admin handlers
+		adminHandler := handlers.NewAdminHandler(svc.Admin)
+		admin := adminOnly.Group("/admin")
+		admin.GET("/circuit-breakers", adminHandler.ListCircuitBreakers)
+		admin.POST("/circuit-breakers/:host/reset", adminHandler.ResetCircuitBreaker)
+		admin.GET("/log-levels", adminHandler.GetLogLevels)
+		admin.PUT("/log-levels", adminHandler.SetLogLevel)
+
+		// API key handlers
+		apiKeyHandler := handlers.NewAPIKeyHandler(svc.APIKeys)
+		admin.POST("/api-keys", apiKeyHandler.CreateKey)
+		admin.GET("/api-keys", apiKeyHandler.ListKeys)
+		admin.GET("/api-keys/:name", apiKeyHandler.GetKey)
+		admin.PUT("/api-keys/:name", apiKeyHandler.UpdateKey)
+		admin.DELETE("/api-keys/:name", apiKeyHandler.DeleteKey)
 	}
 }
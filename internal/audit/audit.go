@@ -0,0 +1,65 @@
+// Package audit records who changed a task, when, from where and what
+// changed, in an append-only log.
+package audit
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader names the actor making an API request.
+const ActorHeader = "X-Actor"
+
+// AnonymousActor is recorded when a request does not name an actor.
+const AnonymousActor = "anonymous"
+
+const maxActorLength = 255
+
+// Actor is whoever made a change, and the address the change came from.
+// Scopes is set when the actor authenticated with an API key. Without API
+// key authentication, and for changes made by the scheduler itself, it is
+// nil and nothing is restricted.
+type Actor struct {
+	Name     string
+	SourceIP string
+	Scopes   models.APIScopes
+}
+
+// Allows reports whether the actor may do what scope grants.
+func (a Actor) Allows(scope models.APIScope) bool {
+	return a.Scopes == nil || a.Scopes.Has(scope)
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor carried by ctx. Changes made outside an API
+// request are attributed to the system.
+func ActorFrom(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Name: "system"}
+}
+
+// GinMiddleware attaches the request's actor to its context. The actor is
+// taken from the X-Actor header, so it is self-reported.
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := strings.TrimSpace(c.GetHeader(ActorHeader))
+		if name == "" || len(name) > maxActorLength {
+			name = AnonymousActor
+		}
+		actor := Actor{Name: name, SourceIP: c.ClientIP()}
+		c.Request = c.Request.WithContext(WithActor(c.Request.Context(), actor))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+// ignoredFields are task fields that change on their own, that repeat the
+// event's actor, or that must not be written to the audit log; the check-in
+// token is a credential.
+var ignoredFields = map[string]bool{
+	"id":            true,
+	"created_at":    true,
+	"created_by":    true,
+	"updated_at":    true,
+	"updated_by":    true,
+	"revision":      true,
+	"next_run":      true,
+	"overdue":       true,
+	"overdue_since": true,
+	"checkin":       true,
+	"counters":      true,
+}
+
+// NewEvent builds the audit event for a change to a task, attributed to the
+// actor carried by ctx. before is nil for a created task. The event is saved
+// in the same transaction as the change, so a change is never saved without
+// its event.
+func NewEvent(ctx context.Context, action models.AuditAction, before, after *models.Task) (*models.AuditEvent, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute audit changes: %w", err)
+	}
+
+	for name := range ignoredFields {
+		delete(changes, name)
+	}
+
+	actor := ActorFrom(ctx)
+	return &models.AuditEvent{
+		ID:         uuid.New(),
+		OccurredAt: time.Now(),
+		Actor:      actor.Name,
+		SourceIP:   actor.SourceIP,
+		Action:     action,
+		TaskID:     after.ID,
+		Changes:    changes,
+	}, nil
+}
+
+// Diff compares the JSON form of two values field by field. A nil before or
+// after reports every field of the other as set or removed.
+func Diff(before, after interface{}) (models.AuditChanges, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := models.AuditChanges{}
+	for name, value := range afterFields {
+		if previous, ok := beforeFields[name]; !ok || !bytes.Equal(previous, value) {
+			changes[name] = models.AuditChange{Before: beforeFields[name], After: value}
+		}
+	}
+	for name, previous := range beforeFields {
+		if _, ok := afterFields[name]; !ok {
+			changes[name] = models.AuditChange{Before: previous}
+		}
+	}
+	return changes, nil
+}
+
+func fields(value interface{}) (map[string]json.RawMessage, error) {
+	values := map[string]json.RawMessage{}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	// A nil value marshals to null, which leaves values empty
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestDiff(t *testing.T) {
+	type value struct {
+		Name    string            `json:"name"`
+		Count   int               `json:"count,omitempty"`
+		Headers map[string]string `json:"headers,omitempty"`
+	}
+
+	tests := []struct {
+		name   string
+		before interface{}
+		after  interface{}
+		want   map[string][2]string // field: before, after
+	}{
+		{
+			name:   "unchanged",
+			before: value{Name: "a", Count: 1},
+			after:  value{Name: "a", Count: 1},
+			want:   map[string][2]string{},
+		},
+		{
+			name:   "changed field",
+			before: value{Name: "a", Count: 1},
+			after:  value{Name: "b", Count: 1},
+			want:   map[string][2]string{"name": {`"a"`, `"b"`}},
+		},
+		{
+			name:   "added field",
+			before: value{Name: "a"},
+			after:  value{Name: "a", Count: 2},
+			want:   map[string][2]string{"count": {"", "2"}},
+		},
+		{
+			name:   "removed field",
+			before: value{Name: "a", Count: 2},
+			after:  value{Name: "a"},
+			want:   map[string][2]string{"count": {"2", ""}},
+		},
+		{
+			name:   "nested change",
+			before: value{Name: "a", Headers: map[string]string{"X": "1"}},
+			after:  value{Name: "a", Headers: map[string]string{"X": "2"}},
+			want:   map[string][2]string{"headers": {`{"X":"1"}`, `{"X":"2"}`}},
+		},
+		{
+			name:   "created",
+			before: (*value)(nil),
+			after:  &value{Name: "a", Count: 1},
+			want:   map[string][2]string{"name": {"", `"a"`}, "count": {"", "1"}},
+		},
+		{
+			name:   "deleted",
+			before: &value{Name: "a"},
+			after:  (*value)(nil),
+			want:   map[string][2]string{"name": {`"a"`, ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := Diff(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if len(changes) != len(tt.want) {
+				t.Errorf("Diff() = %v, want %d changes", changes, len(tt.want))
+			}
+			for name, want := range tt.want {
+				change, ok := changes[name]
+				if !ok {
+					t.Errorf("Diff() has no change to %q", name)
+					continue
+				}
+				if string(change.Before) != want[0] || string(change.After) != want[1] {
+					t.Errorf("Diff()[%q] = %s -> %s, want %s -> %s", name, change.Before, change.After, want[0], want[1])
+				}
+			}
+		})
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	before := &models.Task{
+		ID:        uuid.New(),
+		Name:      "nightly",
+		Status:    models.StatusScheduled,
+		Revision:  3,
+		UpdatedAt: time.Now().Add(-time.Hour),
+	}
+	after := *before
+	after.Status = models.StatusPaused
+	after.Revision = 4
+	after.UpdatedAt = time.Now()
+	after.UpdatedBy = "alice"
+
+	ctx := WithActor(context.Background(), Actor{Name: "alice", SourceIP: "10.0.0.1"})
+	event, err := NewEvent(ctx, models.AuditPause, before, &after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Actor != "alice" || event.SourceIP != "10.0.0.1" || event.TaskID != before.ID || event.Action != models.AuditPause {
+		t.Errorf("NewEvent() = %+v, want alice's pause of the task", event)
+	}
+	// Fields that change on their own or repeat the actor are left out
+	want := map[string]bool{"status": true}
+	for name := range event.Changes {
+		if !want[name] {
+			t.Errorf("NewEvent() changes include %q", name)
+		}
+	}
+	if change := event.Changes["status"]; string(change.After) != `"paused"` {
+		t.Errorf("status change = %s -> %s, want paused", change.Before, change.After)
+	}
+}
+
+func TestNewEventLeavesOutCheckinToken(t *testing.T) {
+	task := &models.Task{ID: uuid.New(), Name: "backup", Checkin: &models.Checkin{Token: "ping-token"}}
+	event, err := NewEvent(context.Background(), models.AuditCreate, nil, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(event.Changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := event.Changes["checkin"]; ok {
+		t.Errorf("NewEvent() changes include the check-in: %s", data)
+	}
+	if event.Actor != "system" {
+		t.Errorf("NewEvent() actor = %q, want system outside a request", event.Actor)
+	}
+}
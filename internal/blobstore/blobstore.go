@@ -0,0 +1,129 @@
+// Package blobstore keeps large response bodies out of Postgres as
+// gzip-compressed files on the local filesystem.
+package blobstore
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+type FSStore struct {
+	dir string
+}
+
+// NewFSStore returns a store rooted at dir, creating it if needed. An empty
+// dir yields a nil store, meaning offloading is disabled.
+func NewFSStore(dir string) (*FSStore, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &FSStore{dir: dir}, nil
+}
+
+// Create opens a new blob for writing. Data written is gzip-compressed; the
+// blob becomes visible only once the writer is closed successfully.
+func (s *FSStore) Create(key string) (io.WriteCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return nil, err
+	}
+	return &blobWriter{file: f, gz: gzip.NewWriter(f), path: path}, nil
+}
+
+// Open returns a reader over the decompressed contents of a blob.
+func (s *FSStore) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &blobReader{file: f, gz: gz}, nil
+}
+
+// Delete removes a blob. Deleting a missing blob is not an error.
+func (s *FSStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *FSStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
+
+type blobWriter struct {
+	file *os.File
+	gz   *gzip.Writer
+	path string
+}
+
+func (w *blobWriter) Write(p []byte) (int, error) {
+	return w.gz.Write(p)
+}
+
+func (w *blobWriter) Close() error {
+	if err := w.gz.Close(); err != nil {
+		w.file.Close()
+		os.Remove(w.file.Name())
+		return err
+	}
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+	return os.Rename(w.file.Name(), w.path)
+}
+
+type blobReader struct {
+	file *os.File
+	gz   *gzip.Reader
+}
+
+func (r *blobReader) Read(p []byte) (int, error) {
+	return r.gz.Read(p)
+}
+
+func (r *blobReader) Close() error {
+	r.gz.Close()
+	return r.file.Close()
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,11 +16,79 @@ type Config struct {
 	Database  DatabaseConfig
 	Scheduler SchedulerConfig
 	Log       LogConfig
+	Secrets   SecretsConfig
+	Executor  ExecutorConfig
+	Storage   StorageConfig
+	Retention RetentionConfig
+	Tracing   TracingConfig
+	Health    HealthConfig
+	Alerting  AlertingConfig
+	Auth      AuthConfig
 }
 
+// AuthConfig controls API key authentication. When Enabled, API requests
+// need a key with the route's scope. BootstrapKey, when set, is accepted as
+// an admin key so the first keys can be created.
+type AuthConfig struct {
+	Enabled      bool
+	BootstrapKey string
+}
+
+// AlertingConfig controls alert delivery. A task's rule notifies at most once
+// per Throttle; each channel is tried up to MaxAttempts times, with Timeout
+// per attempt. Tasks with a heartbeat policy are checked for missed runs
+// every HeartbeatCheckInterval.
+type AlertingConfig struct {
+	Throttle               time.Duration
+	Timeout                time.Duration
+	MaxAttempts            int
+	HeartbeatCheckInterval time.Duration
+}
+
+// HealthConfig bounds how long each readiness check may take.
+type HealthConfig struct {
+	CheckTimeout time.Duration
+}
+
+// TracingConfig selects where spans are exported: "none", "otlp" (OTLP over
+// HTTP to OTLPEndpoint), "stdout" or "file" (JSON lines appended to File).
+type TracingConfig struct {
+	Exporter     string
+	OTLPEndpoint string
+	File         string
+	ServiceName  string
+	SampleRatio  float64
+}
+
+// RetentionConfig sets the default result retention. Zero MaxAgeDays or
+// MaxRows disables that limit; tasks may override either. Monthly result
+// partitions older than PartitionRetentionMonths are dropped, after being
+// exported to ExportDir when it is set. Results older than ArchiveAfterDays
+// are moved to ArchiveDir.
+type RetentionConfig struct {
+	Enabled                  bool
+	MaxAgeDays               int
+	MaxRows                  int
+	Interval                 time.Duration
+	BatchSize                int
+	PartitionsAhead          int
+	PartitionRetentionMonths int
+	ExportDir                string
+	ArchiveDir               string
+	ArchiveAfterDays         int
+}
+
+type StorageConfig struct {
+	BlobDir string
+}
+
+// ServerConfig sets the listen address. TrustedProxies lists the proxy
+// addresses or CIDRs whose X-Forwarded-For header is believed when working
+// out a client's IP; by default none are trusted.
 type ServerConfig struct {
-	Host string
-	Port string
+	Host           string
+	Port           string
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -34,7 +105,60 @@ type SchedulerConfig struct {
 }
 
 type LogConfig struct {
-	Level string
+	Level  string
+	Format string
+	// Packages holds per-package overrides as package=level
+	Packages []string
+}
+
+type ExecutorConfig struct {
+	AllowInsecureTLS bool
+	Egress           EgressConfig
+	CircuitBreaker   CircuitBreakerConfig
+	RateLimit        RateLimitConfig
+	Body             BodyConfig
+}
+
+// BodyConfig bounds how much of a response body is kept. Bodies larger than
+// BlobThresholdBytes are offloaded to the blob store when one is configured.
+type BodyConfig struct {
+	MaxBytes           int64
+	BlobThresholdBytes int64
+	BlobMaxBytes       int64
+}
+
+type RateLimitConfig struct {
+	MaxWait time.Duration
+	Rules   []RateLimitRule
+}
+
+// RateLimitRule limits requests to hosts matching Host ("*.example.com"
+// matches subdomains) to Requests per Per, with at most MaxConcurrent in flight.
+type RateLimitRule struct {
+	Host          string
+	Requests      int
+	Per           time.Duration
+	Burst         int
+	MaxConcurrent int
+}
+
+type CircuitBreakerConfig struct {
+	Enabled          bool
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+type EgressConfig struct {
+	BlockPrivate bool
+	AllowCIDRs   []string
+	DenyCIDRs    []string
+	AllowHosts   []string
+	DenyHosts    []string
+}
+
+type SecretsConfig struct {
+	MasterKey    string
+	PreviousKeys []string
 }
 
 func Load() (*Config, error) {
@@ -43,8 +167,9 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
-			Port: getEnv("SERVER_PORT", "8080"),
+			Host:           getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -58,13 +183,141 @@ func Load() (*Config, error) {
 			Timezone: getEnv("SCHEDULER_TIMEZONE", "UTC"),
 		},
 		Log: LogConfig{
-			Level: getEnv("LOG_LEVEL", "info"),
+			Level:    getEnv("LOG_LEVEL", "info"),
+			Format:   getEnv("LOG_FORMAT", "json"),
+			Packages: getEnvAsList("LOG_PACKAGE_LEVELS"),
+		},
+		Secrets: SecretsConfig{
+			MasterKey:    getEnv("SECRETS_MASTER_KEY", ""),
+			PreviousKeys: getEnvAsList("SECRETS_PREVIOUS_KEYS"),
+		},
+		Executor: ExecutorConfig{
+			AllowInsecureTLS: getEnvAsBool("EXECUTOR_ALLOW_INSECURE_TLS", false),
+			Egress: EgressConfig{
+				BlockPrivate: getEnvAsBool("EGRESS_BLOCK_PRIVATE", true),
+				AllowCIDRs:   getEnvAsList("EGRESS_ALLOW_CIDRS"),
+				DenyCIDRs:    getEnvAsList("EGRESS_DENY_CIDRS"),
+				AllowHosts:   getEnvAsList("EGRESS_ALLOW_HOSTS"),
+				DenyHosts:    getEnvAsList("EGRESS_DENY_HOSTS"),
+			},
+			CircuitBreaker: CircuitBreakerConfig{
+				Enabled:          getEnvAsBool("CIRCUIT_BREAKER_ENABLED", true),
+				FailureThreshold: getEnvAsInt("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 5),
+				Cooldown:         getEnvAsDuration("CIRCUIT_BREAKER_COOLDOWN", time.Minute),
+			},
+			RateLimit: RateLimitConfig{
+				MaxWait: getEnvAsDuration("RATE_LIMIT_MAX_WAIT", 10*time.Second),
+			},
+			Body: BodyConfig{
+				MaxBytes:           int64(getEnvAsInt("RESULT_MAX_BODY_BYTES", 64*1024)),
+				BlobThresholdBytes: int64(getEnvAsInt("RESULT_BLOB_THRESHOLD_BYTES", 64*1024)),
+				BlobMaxBytes:       int64(getEnvAsInt("RESULT_BLOB_MAX_BYTES", 100*1024*1024)),
+			},
+		},
+		Storage: StorageConfig{
+			BlobDir: getEnv("RESULT_BLOB_DIR", ""),
+		},
+		Retention: RetentionConfig{
+			Enabled:    getEnvAsBool("RESULT_RETENTION_ENABLED", true),
+			MaxAgeDays: getEnvAsInt("RESULT_RETENTION_DAYS", 0),
+			MaxRows:    getEnvAsInt("RESULT_RETENTION_MAX_ROWS", 0),
+			Interval:   getEnvAsDuration("RESULT_RETENTION_INTERVAL", time.Hour),
+			BatchSize:  getEnvAsInt("RESULT_RETENTION_BATCH_SIZE", 1000),
+
+			PartitionsAhead:          getEnvAsInt("RESULT_PARTITIONS_AHEAD", 3),
+			PartitionRetentionMonths: getEnvAsInt("RESULT_PARTITION_RETENTION_MONTHS", 0),
+			ExportDir:                getEnv("RESULT_PARTITION_EXPORT_DIR", ""),
+			ArchiveDir:               getEnv("RESULT_ARCHIVE_DIR", ""),
+			ArchiveAfterDays:         getEnvAsInt("RESULT_ARCHIVE_AFTER_DAYS", 0),
+		},
+		Tracing: TracingConfig{
+			Exporter:     getEnv("TRACING_EXPORTER", "none"),
+			OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", ""),
+			File:         getEnv("TRACING_FILE", "traces.jsonl"),
+			ServiceName:  getEnv("TRACING_SERVICE_NAME", "task-scheduler"),
+		},
+		Health: HealthConfig{
+			CheckTimeout: getEnvAsDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		},
+		Alerting: AlertingConfig{
+			Throttle:    getEnvAsDuration("ALERT_THROTTLE", 5*time.Minute),
+			Timeout:     getEnvAsDuration("ALERT_TIMEOUT", 10*time.Second),
+			MaxAttempts: getEnvAsInt("ALERT_MAX_ATTEMPTS", 3),
+
+			HeartbeatCheckInterval: getEnvAsDuration("HEARTBEAT_CHECK_INTERVAL", 30*time.Second),
+		},
+		Auth: AuthConfig{
+			Enabled:      getEnvAsBool("API_AUTH_ENABLED", false),
+			BootstrapKey: getEnv("API_BOOTSTRAP_KEY", ""),
 		},
 	}
 
+	sampleRatio, err := strconv.ParseFloat(getEnv("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil || sampleRatio < 0 || sampleRatio > 1 {
+		return nil, fmt.Errorf("invalid TRACING_SAMPLE_RATIO: must be between 0 and 1")
+	}
+	cfg.Tracing.SampleRatio = sampleRatio
+
+	if cfg.Alerting.MaxAttempts < 1 {
+		return nil, fmt.Errorf("invalid ALERT_MAX_ATTEMPTS: must be at least 1")
+	}
+
+	if cfg.Auth.BootstrapKey != "" && len(cfg.Auth.BootstrapKey) < 32 {
+		return nil, fmt.Errorf("invalid API_BOOTSTRAP_KEY: must be at least 32 characters")
+	}
+
+	rules, err := parseRateLimitRules(os.Getenv("RATE_LIMITS"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.Executor.RateLimit.Rules = rules
+
 	return cfg, nil
 }
 
+// parseRateLimitRules reads a JSON array of rate limit rules, e.g.
+// [{"host":"api.partner.com","requests":10,"per":"1s","burst":20,"max_concurrent":5}]
+func parseRateLimitRules(value string) ([]RateLimitRule, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var raw []struct {
+		Host          string `json:"host"`
+		Requests      int    `json:"requests"`
+		Per           string `json:"per"`
+		Burst         int    `json:"burst"`
+		MaxConcurrent int    `json:"max_concurrent"`
+	}
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMITS: %w", err)
+	}
+
+	rules := make([]RateLimitRule, 0, len(raw))
+	for i, r := range raw {
+		if r.Host == "" {
+			return nil, fmt.Errorf("invalid RATE_LIMITS: rule %d has no host", i)
+		}
+		per := time.Second
+		if r.Per != "" {
+			parsed, err := time.ParseDuration(r.Per)
+			if err != nil || parsed <= 0 {
+				return nil, fmt.Errorf("invalid RATE_LIMITS: rule %d has invalid per %q", i, r.Per)
+			}
+			per = parsed
+		}
+		rules = append(rules, RateLimitRule{
+			Host:          r.Host,
+			Requests:      r.Requests,
+			Per:           per,
+			Burst:         r.Burst,
+			MaxConcurrent: r.MaxConcurrent,
+		})
+	}
+
+	return rules, nil
+}
+
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -95,4 +348,36 @@ func getEnvAsInt(key string, defaultValue int) int {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
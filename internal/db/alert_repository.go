@@ -0,0 +1,129 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+// Alert Repository Methods
+
+// GetAlertStates returns a task's alert state keyed by rule type.
+func (r *Repository) GetAlertStates(taskID uuid.UUID) (map[models.AlertType]*models.AlertState, error) {
+	query := `
+		SELECT task_id, rule_type, firing, fired_at, last_notified_at
+		FROM task_alert_state
+		WHERE task_id = $1
+	`
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := map[models.AlertType]*models.AlertState{}
+	for rows.Next() {
+		state := &models.AlertState{}
+		if err := rows.Scan(&state.TaskID, &state.RuleType, &state.Firing, &state.FiredAt, &state.LastNotifiedAt); err != nil {
+			return nil, err
+		}
+		states[state.RuleType] = state
+	}
+	return states, rows.Err()
+}
+
+func (r *Repository) SaveAlertState(state *models.AlertState) error {
+	query := `
+		INSERT INTO task_alert_state (task_id, rule_type, firing, fired_at, last_notified_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (task_id, rule_type) DO UPDATE SET
+			firing = EXCLUDED.firing,
+			fired_at = EXCLUDED.fired_at,
+			last_notified_at = EXCLUDED.last_notified_at
+	`
+	_, err := r.db.Exec(query, state.TaskID, state.RuleType, state.Firing, state.FiredAt, state.LastNotifiedAt)
+	return err
+}
+
+func (r *Repository) CreateAlertDelivery(delivery *models.AlertDelivery) error {
+	query := `
+		INSERT INTO alert_deliveries (id, channel_name, task_id, result_id, rule_type, event, attempt, success, status_code, error, duration_ms, attempted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	var statusCode sql.NullInt64
+	if delivery.StatusCode != 0 {
+		statusCode = sql.NullInt64{Int64: int64(delivery.StatusCode), Valid: true}
+	}
+	_, err := r.db.Exec(query,
+		delivery.ID,
+		delivery.ChannelName,
+		delivery.TaskID,
+		delivery.ResultID,
+		delivery.RuleType,
+		delivery.Event,
+		delivery.Attempt,
+		delivery.Success,
+		statusCode,
+		nullableString(delivery.Error),
+		delivery.DurationMs,
+		delivery.AttemptedAt,
+	)
+	return err
+}
+
+// ListAlertDeliveries returns a channel's delivery attempts, newest first.
+func (r *Repository) ListAlertDeliveries(channelName string, params models.ListDeliveriesParams) ([]models.AlertDelivery, int, error) {
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM alert_deliveries WHERE channel_name = $1", channelName).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, channel_name, task_id, result_id, rule_type, event, attempt, success, status_code, error, duration_ms, attempted_at
+		FROM alert_deliveries
+		WHERE channel_name = $1
+		ORDER BY attempted_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(query, channelName, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.AlertDelivery{}
+	for rows.Next() {
+		var delivery models.AlertDelivery
+		var statusCode sql.NullInt64
+		var errorMsg sql.NullString
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.ChannelName,
+			&delivery.TaskID,
+			&delivery.ResultID,
+			&delivery.RuleType,
+			&delivery.Event,
+			&delivery.Attempt,
+			&delivery.Success,
+			&statusCode,
+			&errorMsg,
+			&delivery.DurationMs,
+			&delivery.AttemptedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		delivery.StatusCode = int(statusCode.Int64)
+		delivery.Error = errorMsg.String
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, total, rows.Err()
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// APIKey Repository Methods
+
+const apiKeyColumns = "id, name, prefix, scopes, expires_at, last_used_at, created_at, created_by, updated_at"
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.Scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.CreatedAt,
+		&key.CreatedBy,
+		&key.UpdatedAt,
+	)
+	return key, err
+}
+
+// CreateAPIKey saves a key with the hash of its secret value. It fails with
+// "api key already exists" when a key with the same name exists.
+func (r *Repository) CreateAPIKey(key *models.APIKey, keyHash string) error {
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, expires_at, created_at, created_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.Exec(query,
+		key.ID,
+		key.Name,
+		key.Prefix,
+		keyHash,
+		key.Scopes,
+		key.ExpiresAt,
+		key.CreatedAt,
+		key.CreatedBy,
+		key.UpdatedAt,
+	)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return fmt.Errorf("api key already exists")
+	}
+	return err
+}
+
+func (r *Repository) GetAPIKeyByName(name string) (*models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE name = $1"
+	key, err := scanAPIKey(r.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("api key not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *Repository) GetAPIKeyByHash(keyHash string) (*models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1"
+	key, err := scanAPIKey(r.db.QueryRow(query, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("api key not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *Repository) ListAPIKeys() ([]models.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys ORDER BY name ASC"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *Repository) UpdateAPIKey(key *models.APIKey) error {
+	query := "UPDATE api_keys SET scopes = $1, expires_at = $2, updated_at = $3 WHERE id = $4"
+	result, err := r.db.Exec(query, key.Scopes, key.ExpiresAt, key.UpdatedAt, key.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("api key not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteAPIKey(name string) error {
+	result, err := r.db.Exec("DELETE FROM api_keys WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("api key not found")
+	}
+
+	return nil
+}
+
+// TouchAPIKey records that a key was used at the given time. To spare a write
+// on every request, last_used_at only moves once it is a minute old.
+func (r *Repository) TouchAPIKey(id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE api_keys SET last_used_at = $1
+		WHERE id = $2 AND (last_used_at IS NULL OR last_used_at < $3)
+	`
+	_, err := r.db.Exec(query, at, id, at.Add(-time.Minute))
+	return err
+}
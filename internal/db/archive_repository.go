@@ -0,0 +1,125 @@
+package db
+
+import (
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+// Archive Repository Methods
+
+// ListResultArchiveGroups returns each task and month that has results run
+// before cutoff, oldest month first.
+func (r *Repository) ListResultArchiveGroups(cutoff time.Time) ([]models.TaskMonth, error) {
+	query := `
+		SELECT task_id, date_trunc('month', run_at AT TIME ZONE 'UTC') AS month
+		FROM task_results
+		WHERE run_at < $1
+		GROUP BY 1, 2
+		ORDER BY 2, 1
+	`
+	rows, err := r.db.Query(query, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.TaskMonth{}
+	for rows.Next() {
+		var group models.TaskMonth
+		if err := rows.Scan(&group.TaskID, &group.Month); err != nil {
+			return nil, err
+		}
+		// date_trunc on a UTC wall time yields a timestamp without zone
+		group.Month = time.Date(group.Month.Year(), group.Month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+// StreamTaskResults calls fn for every result of a task run in [from, to),
+// oldest first.
+func (r *Repository) StreamTaskResults(taskID uuid.UUID, from, to time.Time, fn func(models.TaskResult) error) error {
+	query := `
+		SELECT ` + taskResultColumns + `
+		FROM task_results
+		WHERE task_id = $1 AND run_at >= $2 AND run_at < $3
+		ORDER BY run_at
+	`
+	rows, err := r.db.Query(query, taskID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result, err := scanTaskResult(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// PruneTaskResultsBetween deletes up to limit of a task's results run in
+// [from, to), oldest first.
+func (r *Repository) PruneTaskResultsBetween(taskID uuid.UUID, from, to time.Time, limit int) (int, []string, error) {
+	return r.pruneTaskResults(`
+		SELECT id FROM task_results
+		WHERE task_id = $1 AND run_at >= $2 AND run_at < $3
+		ORDER BY run_at
+		LIMIT $4
+	`, taskID, from, to, limit)
+}
+
+// RestoreTaskResults inserts archived results, skipping any that already
+// exist. Restored rows are taken back out of the daily rollups so they are
+// not counted twice. It returns the number of rows inserted.
+func (r *Repository) RestoreTaskResults(results []models.TaskResult) (int, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO task_results (` + taskResultColumns + `)
+			VALUES (` + taskResultPlaceholders + `)
+			ON CONFLICT DO NOTHING
+			RETURNING task_id, run_at, success, duration_ms
+		),
+		unrolled AS (
+			UPDATE task_result_daily d SET
+				run_count = d.run_count - 1,
+				success_count = d.success_count - CASE WHEN i.success THEN 1 ELSE 0 END,
+				failure_count = d.failure_count - CASE WHEN i.success THEN 0 ELSE 1 END,
+				total_duration_ms = d.total_duration_ms - i.duration_ms
+			FROM inserted i
+			WHERE d.task_id = i.task_id AND d.day = (i.run_at AT TIME ZONE 'UTC')::date
+		)
+		SELECT COUNT(*) FROM inserted
+	`
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	restored := 0
+	for i := range results {
+		var inserted int
+		if err := tx.QueryRow(query, taskResultArgs(&results[i])...).Scan(&inserted); err != nil {
+			return 0, err
+		}
+		restored += inserted
+	}
+
+	if _, err := tx.Exec("DELETE FROM task_result_daily WHERE run_count <= 0"); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return restored, nil
+}
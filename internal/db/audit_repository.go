@@ -0,0 +1,116 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// Audit Repository Methods
+
+// insertAuditEvent saves event within the transaction of the change it
+// describes.
+func insertAuditEvent(tx *Tx, event *models.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (id, occurred_at, actor, source_ip, action, task_id, changes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := tx.Exec(query,
+		event.ID,
+		event.OccurredAt,
+		event.Actor,
+		nullableString(event.SourceIP),
+		event.Action,
+		event.TaskID,
+		event.Changes,
+	)
+	return err
+}
+
+// ListAuditEvents returns audit events, newest first.
+func (r *Repository) ListAuditEvents(params models.ListAuditParams) ([]models.AuditEvent, int, error) {
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 10
+	}
+
+	offset := (params.Page - 1) * params.Limit
+
+	conditions := []string{}
+	args := []interface{}{}
+	argCount := 1
+
+	if params.TaskID != nil {
+		conditions = append(conditions, fmt.Sprintf("task_id = $%d", argCount))
+		args = append(args, *params.TaskID)
+		argCount++
+	}
+
+	if params.Actor != "" {
+		conditions = append(conditions, fmt.Sprintf("actor = $%d", argCount))
+		args = append(args, params.Actor)
+		argCount++
+	}
+
+	if params.Action != "" {
+		conditions = append(conditions, fmt.Sprintf("action = $%d", argCount))
+		args = append(args, params.Action)
+		argCount++
+	}
+
+	if params.DateFrom != nil {
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", argCount))
+		args = append(args, *params.DateFrom)
+		argCount++
+	}
+
+	if params.DateTo != nil {
+		conditions = append(conditions, fmt.Sprintf("occurred_at <= $%d", argCount))
+		args = append(args, *params.DateTo)
+		argCount++
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM audit_events %s", whereClause)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, occurred_at, actor, source_ip, action, task_id, changes
+		FROM audit_events
+		%s
+		ORDER BY occurred_at DESC
+		LIMIT $%d OFFSET $%d
+	`, whereClause, argCount, argCount+1)
+
+	args = append(args, params.Limit, offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []models.AuditEvent{}
+	for rows.Next() {
+		var event models.AuditEvent
+		var sourceIP sql.NullString
+		if err := rows.Scan(&event.ID, &event.OccurredAt, &event.Actor, &sourceIP, &event.Action, &event.TaskID, &event.Changes); err != nil {
+			return nil, 0, err
+		}
+		event.SourceIP = sourceIP.String
+		events = append(events, event)
+	}
+
+	return events, total, rows.Err()
+}
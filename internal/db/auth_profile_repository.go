@@ -0,0 +1,129 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/lib/pq"
+)
+
+// AuthProfile Repository Methods
+
+const authProfileColumns = "id, name, token_url, client_id, client_secret_name, scopes, audience, created_at, updated_at"
+
+func scanAuthProfile(row rowScanner) (models.AuthProfile, error) {
+	var profile models.AuthProfile
+	err := row.Scan(
+		&profile.ID,
+		&profile.Name,
+		&profile.TokenURL,
+		&profile.ClientID,
+		&profile.ClientSecretName,
+		pq.Array(&profile.Scopes),
+		&profile.Audience,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	return profile, err
+}
+
+func (r *Repository) CreateAuthProfile(profile *models.AuthProfile) error {
+	query := `
+		INSERT INTO auth_profiles (id, name, token_url, client_id, client_secret_name, scopes, audience, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := r.db.Exec(query,
+		profile.ID,
+		profile.Name,
+		profile.TokenURL,
+		profile.ClientID,
+		profile.ClientSecretName,
+		pq.Array(profile.Scopes),
+		profile.Audience,
+		profile.CreatedAt,
+		profile.UpdatedAt,
+	)
+	return err
+}
+
+func (r *Repository) GetAuthProfileByName(name string) (*models.AuthProfile, error) {
+	query := "SELECT " + authProfileColumns + " FROM auth_profiles WHERE name = $1"
+	profile, err := scanAuthProfile(r.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("auth profile not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *Repository) ListAuthProfiles() ([]models.AuthProfile, error) {
+	query := "SELECT " + authProfileColumns + " FROM auth_profiles ORDER BY name ASC"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := []models.AuthProfile{}
+	for rows.Next() {
+		profile, err := scanAuthProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func (r *Repository) UpdateAuthProfile(profile *models.AuthProfile) error {
+	query := `
+		UPDATE auth_profiles
+		SET token_url = $1, client_id = $2, client_secret_name = $3, scopes = $4, audience = $5, updated_at = $6
+		WHERE name = $7
+	`
+	result, err := r.db.Exec(query,
+		profile.TokenURL,
+		profile.ClientID,
+		profile.ClientSecretName,
+		pq.Array(profile.Scopes),
+		profile.Audience,
+		profile.UpdatedAt,
+		profile.Name,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("auth profile not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteAuthProfile(name string) error {
+	result, err := r.db.Exec("DELETE FROM auth_profiles WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("auth profile not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// NotificationChannel Repository Methods
+
+const channelColumns = "id, name, type, config, created_at, updated_at"
+
+func scanChannel(row rowScanner) (models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	err := row.Scan(
+		&channel.ID,
+		&channel.Name,
+		&channel.Type,
+		&channel.Config,
+		&channel.CreatedAt,
+		&channel.UpdatedAt,
+	)
+	return channel, err
+}
+
+func (r *Repository) CreateChannel(channel *models.NotificationChannel) error {
+	query := `
+		INSERT INTO notification_channels (id, name, type, config, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(query,
+		channel.ID,
+		channel.Name,
+		channel.Type,
+		channel.Config,
+		channel.CreatedAt,
+		channel.UpdatedAt,
+	)
+	return err
+}
+
+func (r *Repository) GetChannelByName(name string) (*models.NotificationChannel, error) {
+	query := "SELECT " + channelColumns + " FROM notification_channels WHERE name = $1"
+	channel, err := scanChannel(r.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("channel not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+func (r *Repository) ListChannels() ([]models.NotificationChannel, error) {
+	query := "SELECT " + channelColumns + " FROM notification_channels ORDER BY name ASC"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := []models.NotificationChannel{}
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
+
+func (r *Repository) UpdateChannel(channel *models.NotificationChannel) error {
+	query := `
+		UPDATE notification_channels
+		SET config = $1, updated_at = $2
+		WHERE name = $3
+	`
+	result, err := r.db.Exec(query, channel.Config, channel.UpdatedAt, channel.Name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("channel not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteChannel(name string) error {
+	result, err := r.db.Exec("DELETE FROM notification_channels WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("channel not found")
+	}
+
+	return nil
+}
+
+// ListTasksUsingChannel returns the names of active tasks whose alert rules
+// notify the channel.
+func (r *Repository) ListTasksUsingChannel(name string) ([]string, error) {
+	match, err := json.Marshal([]map[string][]string{{"channels": {name}}})
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT name FROM tasks
+		WHERE alert_rules @> $1::jsonb AND status <> $2
+		ORDER BY name
+	`
+	rows, err := r.db.Query(query, string(match), models.StatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
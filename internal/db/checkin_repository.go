@@ -0,0 +1,61 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+// Check-in Repository Methods
+
+func (r *Repository) GetTaskByCheckinToken(token string) (*models.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE checkin_token = $1"
+	task, err := scanTask(r.db.QueryRow(query, token))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("check-in not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// StartCheckin records a start ping. The next success or fail ping measures
+// its duration from here.
+func (r *Repository) StartCheckin(taskID uuid.UUID, at time.Time) error {
+	_, err := r.db.Exec("UPDATE tasks SET checkin_started_at = $1, last_ping_at = $1 WHERE id = $2", at, taskID)
+	return err
+}
+
+// FinishCheckin records a success or fail ping and ends any started run.
+func (r *Repository) FinishCheckin(taskID uuid.UUID, status models.CheckinStatus, at time.Time) error {
+	query := `
+		UPDATE tasks
+		SET checkin_status = $1, checkin_started_at = NULL, last_ping_at = $2
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(query, status, at, taskID)
+	return err
+}
+
+// MarkCheckin sets a monitor's status unless a ping has arrived since
+// lastPingAt was read. It reports whether the status was changed.
+func (r *Repository) MarkCheckin(taskID uuid.UUID, status models.CheckinStatus, lastPingAt *time.Time) (bool, error) {
+	query := `
+		UPDATE tasks
+		SET checkin_status = $1
+		WHERE id = $2 AND checkin_status <> $1 AND last_ping_at IS NOT DISTINCT FROM $3
+	`
+	result, err := r.db.Exec(query, status, taskID, lastPingAt)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,14 @@
+package db
+
+// Health Repository Methods
+
+// Ping checks that the database is reachable.
+func (r *Repository) Ping() error {
+	return r.db.PingContext(r.db.context())
+}
+
+// PendingMigrations returns the migrations in migrationsPath that have not
+// been applied.
+func (r *Repository) PendingMigrations(migrationsPath string) ([]string, error) {
+	return r.db.PendingMigrations(migrationsPath)
+}
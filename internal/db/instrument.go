@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/metrics"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The methods below shadow those of the embedded *sql.DB so every statement
+// runs under the DB's context, is traced, and has its latency recorded,
+// labelled with the calling repository method.
+
+// WithContext returns a DB whose statements run under ctx.
+func (db *DB) WithContext(ctx context.Context) *DB {
+	return &DB{DB: db.DB, ctx: ctx}
+}
+
+func (db *DB) context() context.Context {
+	if db.ctx == nil {
+		return context.Background()
+	}
+	return db.ctx
+}
+
+func (db *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := observe(db.context(), callerOperation(), query)
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+func (db *DB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := observe(db.context(), callerOperation(), query)
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
+	ctx, done := observe(db.context(), callerOperation(), query)
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+// Tx is a transaction whose statements are recorded under the operation that
+// began it.
+type Tx struct {
+	*sql.Tx
+	ctx       context.Context
+	operation string
+}
+
+func (db *DB) Begin() (*Tx, error) {
+	tx, err := db.DB.BeginTx(db.context(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx, ctx: db.context(), operation: callerOperation()}, nil
+}
+
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := observe(tx.ctx, tx.operation, query)
+	result, err := tx.Tx.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, done := observe(tx.ctx, tx.operation, query)
+	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+func (tx *Tx) QueryRow(query string, args ...interface{}) *sql.Row {
+	ctx, done := observe(tx.ctx, tx.operation, query)
+	row := tx.Tx.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+// observe starts a client span for a statement when ctx is part of a trace.
+// The returned function ends it and records the statement's latency.
+func observe(ctx context.Context, operation, query string) (context.Context, func(error)) {
+	start := time.Now()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, func(error) {
+			metrics.ObserveDBQuery(operation, start)
+		}
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "db."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", strings.Join(strings.Fields(query), " ")),
+		),
+	)
+	return ctx, func(err error) {
+		metrics.ObserveDBQuery(operation, start)
+		tracing.End(span, err)
+	}
+}
+
+// callerOperation returns the name of the function that called the DB or Tx
+// method, e.g. "CreateTaskResult" for (*Repository).CreateTaskResult.
+func callerOperation() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	parts := strings.Split(name, ".")
+	switch {
+	case len(parts) >= 3 && strings.HasPrefix(parts[1], "("):
+		return parts[2]
+	case len(parts) >= 2:
+		return parts[1]
+	}
+	return name
+}
@@ -0,0 +1 @@
+ALTER TABLE task_results ADD COLUMN IF NOT EXISTS request JSONB;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_results_error_type;
+ALTER TABLE task_results DROP COLUMN IF EXISTS error_type;
@@ -0,0 +1,3 @@
+ALTER TABLE task_results ADD COLUMN IF NOT EXISTS error_type VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_task_results_error_type ON task_results(error_type) WHERE error_type IS NOT NULL;
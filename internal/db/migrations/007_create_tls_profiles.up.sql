@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tls_profiles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    client_cert TEXT NOT NULL DEFAULT '',
+    client_key_secret VARCHAR(255) NOT NULL DEFAULT '',
+    ca_bundle TEXT NOT NULL DEFAULT '',
+    server_name VARCHAR(255) NOT NULL DEFAULT '',
+    min_version VARCHAR(8) NOT NULL DEFAULT '',
+    insecure_skip_verify BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+ALTER TABLE task_results
+    DROP COLUMN IF EXISTS body_blob_key,
+    DROP COLUMN IF EXISTS body_truncated,
+    DROP COLUMN IF EXISTS body_sha256,
+    DROP COLUMN IF EXISTS body_size;
@@ -0,0 +1,5 @@
+ALTER TABLE task_results
+    ADD COLUMN IF NOT EXISTS body_size BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS body_sha256 VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS body_truncated BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS body_blob_key TEXT;
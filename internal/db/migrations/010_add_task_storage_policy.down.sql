@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS task_run_counters;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS storage_policy;
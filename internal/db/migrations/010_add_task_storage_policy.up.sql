@@ -0,0 +1,24 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS storage_policy JSONB;
+
+CREATE TABLE IF NOT EXISTS task_run_counters (
+    task_id UUID PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    run_count BIGINT NOT NULL DEFAULT 0,
+    success_count BIGINT NOT NULL DEFAULT 0,
+    failure_count BIGINT NOT NULL DEFAULT 0,
+    stored_count BIGINT NOT NULL DEFAULT 0,
+    total_duration_ms BIGINT NOT NULL DEFAULT 0,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_success BOOLEAN
+);
+
+INSERT INTO task_run_counters (task_id, run_count, success_count, failure_count, stored_count, total_duration_ms, last_run_at)
+SELECT task_id,
+       COUNT(*),
+       COUNT(*) FILTER (WHERE success),
+       COUNT(*) FILTER (WHERE NOT success),
+       COUNT(*),
+       COALESCE(SUM(duration_ms), 0),
+       MAX(run_at)
+FROM task_results
+GROUP BY task_id
+ON CONFLICT (task_id) DO NOTHING;
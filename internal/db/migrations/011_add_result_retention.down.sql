@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_task_results_task_id_run_at;
+
+DROP TABLE IF EXISTS task_result_daily;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS retention_policy;
@@ -0,0 +1,15 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS retention_policy JSONB;
+
+CREATE TABLE IF NOT EXISTS task_result_daily (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    run_count BIGINT NOT NULL DEFAULT 0,
+    success_count BIGINT NOT NULL DEFAULT 0,
+    failure_count BIGINT NOT NULL DEFAULT 0,
+    total_duration_ms BIGINT NOT NULL DEFAULT 0,
+    min_duration_ms BIGINT,
+    max_duration_ms BIGINT,
+    PRIMARY KEY (task_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_results_task_id_run_at ON task_results(task_id, run_at);
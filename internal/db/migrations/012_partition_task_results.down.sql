@@ -0,0 +1,34 @@
+ALTER TABLE task_results RENAME TO task_results_partitioned;
+
+CREATE TABLE task_results (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL DEFAULT false,
+    response_headers JSONB,
+    response_body TEXT,
+    body_size BIGINT NOT NULL DEFAULT 0,
+    body_sha256 VARCHAR(64),
+    body_truncated BOOLEAN NOT NULL DEFAULT false,
+    body_blob_key TEXT,
+    error_message TEXT,
+    error_type VARCHAR(50),
+    duration_ms BIGINT NOT NULL,
+    request JSONB,
+    timing JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO task_results
+SELECT id, task_id, run_at, status_code, success, response_headers, response_body, body_size, body_sha256, body_truncated, body_blob_key, error_message, error_type, duration_ms, request, timing, created_at
+FROM task_results_partitioned;
+
+DROP TABLE task_results_partitioned;
+
+CREATE INDEX idx_task_results_task_id ON task_results(task_id);
+CREATE INDEX idx_task_results_run_at ON task_results(run_at);
+CREATE INDEX idx_task_results_success ON task_results(success);
+CREATE INDEX idx_task_results_created_at ON task_results(created_at);
+CREATE INDEX idx_task_results_error_type ON task_results(error_type) WHERE error_type IS NOT NULL;
+CREATE INDEX idx_task_results_task_id_run_at ON task_results(task_id, run_at);
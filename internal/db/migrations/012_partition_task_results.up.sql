@@ -0,0 +1,64 @@
+-- Convert task_results to a table range-partitioned by month on run_at.
+-- The primary key must include the partition key, so it becomes (id, run_at).
+ALTER TABLE task_results RENAME TO task_results_unpartitioned;
+
+CREATE TABLE task_results (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL DEFAULT false,
+    response_headers JSONB,
+    response_body TEXT,
+    body_size BIGINT NOT NULL DEFAULT 0,
+    body_sha256 VARCHAR(64),
+    body_truncated BOOLEAN NOT NULL DEFAULT false,
+    body_blob_key TEXT,
+    error_message TEXT,
+    error_type VARCHAR(50),
+    duration_ms BIGINT NOT NULL,
+    request JSONB,
+    timing JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (id, run_at)
+) PARTITION BY RANGE (run_at);
+
+-- Monthly partitions from the oldest existing result through three months ahead.
+-- Later months are created by the retention job.
+DO $$
+DECLARE
+    month_start TIMESTAMP WITH TIME ZONE;
+    last_month TIMESTAMP WITH TIME ZONE;
+BEGIN
+    SELECT date_trunc('month', COALESCE(MIN(run_at), NOW()) AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+    INTO month_start
+    FROM task_results_unpartitioned;
+
+    last_month := (date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '3 months') AT TIME ZONE 'UTC';
+
+    WHILE month_start <= last_month LOOP
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF task_results FOR VALUES FROM (%L) TO (%L)',
+            'task_results_p' || to_char(month_start AT TIME ZONE 'UTC', 'YYYY_MM'),
+            month_start,
+            ((month_start AT TIME ZONE 'UTC') + INTERVAL '1 month') AT TIME ZONE 'UTC'
+        );
+        month_start := ((month_start AT TIME ZONE 'UTC') + INTERVAL '1 month') AT TIME ZONE 'UTC';
+    END LOOP;
+END $$;
+
+-- Catches rows outside every monthly partition so inserts never fail.
+CREATE TABLE IF NOT EXISTS task_results_default PARTITION OF task_results DEFAULT;
+
+INSERT INTO task_results (id, task_id, run_at, status_code, success, response_headers, response_body, body_size, body_sha256, body_truncated, body_blob_key, error_message, error_type, duration_ms, request, timing, created_at)
+SELECT id, task_id, run_at, status_code, success, response_headers, response_body, body_size, body_sha256, body_truncated, body_blob_key, error_message, error_type, duration_ms, request, timing, created_at
+FROM task_results_unpartitioned;
+
+DROP TABLE task_results_unpartitioned;
+
+CREATE INDEX idx_task_results_task_id ON task_results(task_id);
+CREATE INDEX idx_task_results_run_at ON task_results(run_at);
+CREATE INDEX idx_task_results_success ON task_results(success);
+CREATE INDEX idx_task_results_created_at ON task_results(created_at);
+CREATE INDEX idx_task_results_error_type ON task_results(error_type) WHERE error_type IS NOT NULL;
+CREATE INDEX idx_task_results_task_id_run_at ON task_results(task_id, run_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_results_trace_id;
+
+ALTER TABLE task_results DROP COLUMN IF EXISTS trace_id;
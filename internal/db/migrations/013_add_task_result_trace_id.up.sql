@@ -0,0 +1,3 @@
+ALTER TABLE task_results ADD COLUMN IF NOT EXISTS trace_id VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_task_results_trace_id ON task_results(trace_id) WHERE trace_id IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS alert_deliveries;
+
+DROP TABLE IF EXISTS task_alert_state;
+
+ALTER TABLE task_run_counters DROP COLUMN IF EXISTS consecutive_failures;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS alert_rules;
+
+DROP TABLE IF EXISTS notification_channels;
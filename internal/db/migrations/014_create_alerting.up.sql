@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS notification_channels (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL,
+    config JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS alert_rules JSONB;
+
+ALTER TABLE task_run_counters ADD COLUMN IF NOT EXISTS consecutive_failures BIGINT NOT NULL DEFAULT 0;
+
+-- Backfill streaks from the results that are still stored
+UPDATE task_run_counters c
+SET consecutive_failures = (
+    SELECT COUNT(*)
+    FROM task_results r
+    WHERE r.task_id = c.task_id
+      AND NOT r.success
+      AND r.run_at > COALESCE(
+          (SELECT MAX(s.run_at) FROM task_results s WHERE s.task_id = c.task_id AND s.success),
+          '-infinity'
+      )
+);
+
+CREATE TABLE IF NOT EXISTS task_alert_state (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    rule_type VARCHAR(50) NOT NULL,
+    firing BOOLEAN NOT NULL DEFAULT FALSE,
+    fired_at TIMESTAMP WITH TIME ZONE,
+    last_notified_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (task_id, rule_type)
+);
+
+CREATE TABLE IF NOT EXISTS alert_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    channel_name VARCHAR(255) NOT NULL,
+    task_id UUID REFERENCES tasks(id) ON DELETE CASCADE,
+    result_id UUID,
+    rule_type VARCHAR(50) NOT NULL DEFAULT '',
+    event VARCHAR(20) NOT NULL,
+    attempt INTEGER NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    attempted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_deliveries_channel_attempted_at ON alert_deliveries(channel_name, attempted_at DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tasks_heartbeat;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS overdue_since;
+ALTER TABLE tasks DROP COLUMN IF EXISTS heartbeat_policy;
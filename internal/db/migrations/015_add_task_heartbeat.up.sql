@@ -0,0 +1,4 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS heartbeat_policy JSONB;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS overdue_since TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_heartbeat ON tasks(id) WHERE heartbeat_policy IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tasks_type;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS last_ping_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS checkin_started_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS checkin_status;
+ALTER TABLE tasks DROP COLUMN IF EXISTS checkin_token;
+ALTER TABLE tasks DROP COLUMN IF EXISTS type;
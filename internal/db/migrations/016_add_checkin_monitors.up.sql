@@ -0,0 +1,7 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'http';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS checkin_token VARCHAR(64) UNIQUE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS checkin_status VARCHAR(20);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS checkin_started_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS last_ping_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_type ON tasks(type);
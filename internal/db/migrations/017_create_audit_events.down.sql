@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS audit_events;
+
+DROP FUNCTION IF EXISTS audit_events_append_only();
+
+-- Postgres can't drop an enum value, so 'paused' stays on task_status
+UPDATE tasks SET status = 'scheduled' WHERE status = 'paused';
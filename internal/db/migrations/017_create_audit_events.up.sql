@@ -0,0 +1,28 @@
+-- Tasks can be paused and resumed
+ALTER TYPE task_status ADD VALUE IF NOT EXISTS 'paused' BEFORE 'cancelled';
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    actor VARCHAR(255) NOT NULL,
+    source_ip VARCHAR(64),
+    action VARCHAR(20) NOT NULL,
+    task_id UUID NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events(occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_task_occurred_at ON audit_events(task_id, occurred_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_occurred_at ON audit_events(actor, occurred_at DESC);
+
+-- Audit events are append-only
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,7 @@
+ALTER TABLE task_results DROP COLUMN IF EXISTS task_version;
+
+DROP TABLE IF EXISTS task_versions;
+
+DROP FUNCTION IF EXISTS task_versions_immutable();
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
@@ -0,0 +1,31 @@
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS task_versions (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    trigger JSONB NOT NULL,
+    action JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(255) NOT NULL,
+    restored_from INTEGER,
+    PRIMARY KEY (task_id, version)
+);
+
+-- Existing configurations become version 1
+INSERT INTO task_versions (task_id, version, trigger, action, created_at, created_by)
+SELECT id, version, trigger, action, updated_at, 'system' FROM tasks
+ON CONFLICT DO NOTHING;
+
+-- Versions are immutable
+CREATE OR REPLACE FUNCTION task_versions_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'task_versions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS task_versions_immutable ON task_versions;
+CREATE TRIGGER task_versions_immutable
+    BEFORE UPDATE ON task_versions
+    FOR EACH ROW EXECUTE FUNCTION task_versions_immutable();
+
+ALTER TABLE task_results ADD COLUMN IF NOT EXISTS task_version INTEGER;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE tasks DROP COLUMN IF EXISTS created_by;
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_run_hourly_hour;
+
+DROP TABLE IF EXISTS task_run_hourly;
@@ -0,0 +1,35 @@
+-- Hourly run counts are written for every run, whether or not the result
+-- row is stored, and are never pruned.
+CREATE TABLE IF NOT EXISTS task_run_hourly (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    run_count BIGINT NOT NULL DEFAULT 0,
+    success_count BIGINT NOT NULL DEFAULT 0,
+    failure_count BIGINT NOT NULL DEFAULT 0,
+    total_duration_ms BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (task_id, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_run_hourly_hour ON task_run_hourly(hour);
+
+-- Backfill from stored results. Results that were already pruned or archived
+-- only survive as daily rollups, so they are counted at midnight UTC.
+INSERT INTO task_run_hourly (task_id, hour, run_count, success_count, failure_count, total_duration_ms)
+SELECT task_id,
+       date_trunc('hour', run_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC',
+       COUNT(*),
+       COUNT(*) FILTER (WHERE success),
+       COUNT(*) FILTER (WHERE NOT success),
+       COALESCE(SUM(duration_ms), 0)
+FROM task_results
+GROUP BY 1, 2;
+
+INSERT INTO task_run_hourly (task_id, hour, run_count, success_count, failure_count, total_duration_ms)
+SELECT task_id, day::timestamp AT TIME ZONE 'UTC', run_count, success_count, failure_count, total_duration_ms
+FROM task_result_daily
+WHERE run_count > 0
+ON CONFLICT (task_id, hour) DO UPDATE SET
+    run_count = task_run_hourly.run_count + EXCLUDED.run_count,
+    success_count = task_run_hourly.success_count + EXCLUDED.success_count,
+    failure_count = task_run_hourly.failure_count + EXCLUDED.failure_count,
+    total_duration_ms = task_run_hourly.total_duration_ms + EXCLUDED.total_duration_ms;
@@ -0,0 +1,3 @@
+-- Bumped on every change to a task's row, so updates can detect that the
+-- task changed since it was read
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_task_results_run_at_id;
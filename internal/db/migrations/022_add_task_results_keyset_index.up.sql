@@ -0,0 +1,2 @@
+-- Serves cursor pagination of results, newest first
+CREATE INDEX IF NOT EXISTS idx_task_results_run_at_id ON task_results(run_at DESC, id DESC);
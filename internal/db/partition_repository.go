@@ -0,0 +1,132 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/lib/pq"
+)
+
+// Result Partition Repository Methods
+
+const resultPartitionPrefix = "task_results_p"
+
+// resultPartitionFor returns the monthly partition that holds results run in
+// the month containing t.
+func resultPartitionFor(t time.Time) models.ResultPartition {
+	t = t.UTC()
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return models.ResultPartition{
+		Name: resultPartitionPrefix + from.Format("2006_01"),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+// EnsureResultPartition creates the monthly partition for the month
+// containing t if it does not exist yet.
+func (r *Repository) EnsureResultPartition(t time.Time) error {
+	partition := resultPartitionFor(t)
+	query := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s PARTITION OF task_results FOR VALUES FROM (%s) TO (%s)",
+		pq.QuoteIdentifier(partition.Name),
+		pq.QuoteLiteral(partition.From.Format(time.RFC3339)),
+		pq.QuoteLiteral(partition.To.Format(time.RFC3339)),
+	)
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// ListResultPartitions returns the monthly partitions of task_results, oldest
+// first. The default partition is not included.
+func (r *Repository) ListResultPartitions() ([]models.ResultPartition, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = 'task_results'
+		ORDER BY c.relname
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	partitions := []models.ResultPartition{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(name, resultPartitionPrefix) {
+			continue
+		}
+		month, err := time.Parse("2006_01", strings.TrimPrefix(name, resultPartitionPrefix))
+		if err != nil {
+			continue
+		}
+		partitions = append(partitions, resultPartitionFor(month))
+	}
+	return partitions, rows.Err()
+}
+
+// StreamResultPartition calls fn for every result in a partition, oldest first.
+func (r *Repository) StreamResultPartition(partition models.ResultPartition, fn func(models.TaskResult) error) error {
+	query := "SELECT " + taskResultColumns + " FROM " + pq.QuoteIdentifier(partition.Name) + " ORDER BY run_at"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result, err := scanTaskResult(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DropResultPartition adds a partition's results to the daily rollups, then
+// detaches and drops it. It returns the body blob keys of the dropped results.
+func (r *Repository) DropResultPartition(partition models.ResultPartition) ([]string, error) {
+	table := pq.QuoteIdentifier(partition.Name)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(dailyRollupInsert(table)); err != nil {
+		return nil, fmt.Errorf("failed to roll up %s: %w", partition.Name, err)
+	}
+
+	var blobKeys []string
+	err = tx.QueryRow(
+		"SELECT COALESCE(array_agg(body_blob_key) FILTER (WHERE body_blob_key IS NOT NULL), '{}') FROM " + table,
+	).Scan(pq.Array(&blobKeys))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("ALTER TABLE task_results DETACH PARTITION " + table); err != nil {
+		return nil, fmt.Errorf("failed to detach %s: %w", partition.Name, err)
+	}
+	if _, err := tx.Exec("DROP TABLE " + table); err != nil {
+		return nil, fmt.Errorf("failed to drop %s: %w", partition.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return blobKeys, nil
+}
@@ -1,19 +1,23 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/ayushsarode/task-scheduler/internal/logging"
 	_ "github.com/lib/pq"
 )
 
+var logger = logging.For("db")
+
 type DB struct {
 	*sql.DB
+	ctx context.Context
 }
 
 func NewPostgresDB(dsn string) (*DB, error) {
@@ -26,9 +30,9 @@ func NewPostgresDB(dsn string) (*DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Successfully connected to PostgreSQL database")
+	logger.Info("Connected to PostgreSQL database")
 
-	return &DB{db}, nil
+	return &DB{DB: db}, nil
 }
 
 func (db *DB) RunMigrations(migrationsPath string) error {
@@ -43,47 +47,26 @@ func (db *DB) RunMigrations(migrationsPath string) error {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
 
-	// Get all applied migrations
-	appliedMigrations := make(map[string]bool)
-	rows, err := db.Query("SELECT version FROM schema_migrations")
+	appliedMigrations, err := db.appliedMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to query migrations: %w", err)
+		return err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
-			return err
-		}
-		appliedMigrations[version] = true
-	}
-
-	// Read migration files
-	files, err := os.ReadDir(migrationsPath)
+	upFiles, err := migrationFiles(migrationsPath)
 	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+		return err
 	}
 
-	// Filter and sort .up.sql files
-	var upFiles []string
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".up.sql") {
-			upFiles = append(upFiles, file.Name())
-		}
-	}
-	sort.Strings(upFiles)
-
 	// Apply migrations
 	for _, file := range upFiles {
 		version := strings.TrimSuffix(file, ".up.sql")
 
 		if appliedMigrations[version] {
-			log.Printf("Migration %s already applied, skipping", version)
+			logger.Debug("Migration already applied, skipping", "version", version)
 			continue
 		}
 
-		log.Printf("Applying migration: %s", file)
+		logger.Info("Applying migration", "file", file)
 
 		content, err := os.ReadFile(filepath.Join(migrationsPath, file))
 		if err != nil {
@@ -109,13 +92,70 @@ func (db *DB) RunMigrations(migrationsPath string) error {
 			return fmt.Errorf("failed to commit migration %s: %w", file, err)
 		}
 
-		log.Printf("Successfully applied migration: %s", file)
+		logger.Info("Applied migration", "file", file)
 	}
 
 	return nil
 }
 
+// PendingMigrations returns the versions of migrations in migrationsPath that
+// have not been applied, in order.
+func (db *DB) PendingMigrations(migrationsPath string) ([]string, error) {
+	appliedMigrations, err := db.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	upFiles, err := migrationFiles(migrationsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []string{}
+	for _, file := range upFiles {
+		if version := strings.TrimSuffix(file, ".up.sql"); !appliedMigrations[version] {
+			pending = append(pending, version)
+		}
+	}
+	return pending, nil
+}
+
+func (db *DB) appliedMigrations() (map[string]bool, error) {
+	appliedMigrations := make(map[string]bool)
+	rows, err := db.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		appliedMigrations[version] = true
+	}
+	return appliedMigrations, rows.Err()
+}
+
+// migrationFiles returns the sorted names of the .up.sql files in migrationsPath.
+func migrationFiles(migrationsPath string) ([]string, error) {
+	files, err := os.ReadDir(migrationsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var upFiles []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".up.sql") {
+			upFiles = append(upFiles, file.Name())
+		}
+	}
+	sort.Strings(upFiles)
+	return upFiles, nil
+}
+
 func (db *DB) Close() error {
-	log.Println("Closing database connection")
+	logger.Info("Closing database connection")
 	return db.DB.Close()
 }
\ No newline at end of file
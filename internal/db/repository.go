@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/lib/pq"
 )
 
 type Repository struct {
@@ -19,47 +21,120 @@ func NewRepository(db *DB) *Repository {
 	return &Repository{db: db}
 }
 
+// WithContext returns a repository whose queries run under ctx, so they are
+// cancelled with it and traced as its children.
+func (r *Repository) WithContext(ctx context.Context) *Repository {
+	return &Repository{db: r.db.WithContext(ctx)}
+}
+
 // Task Repository Methods
 
-func (r *Repository) CreateTask(task *models.Task) error {
+const taskColumns = "id, name, type, trigger, action, status, created_at, updated_at, next_run, storage_policy, retention_policy, alert_rules, heartbeat_policy, overdue_since, checkin_token, checkin_status, checkin_started_at, last_ping_at, version, revision, created_by, updated_by"
+
+func scanTask(row rowScanner) (models.Task, error) {
+	var task models.Task
+	var checkinToken, checkinStatus sql.NullString
+	var checkin models.Checkin
+	var createdBy, updatedBy sql.NullString
+	err := row.Scan(
+		&task.ID,
+		&task.Name,
+		&task.Type,
+		&task.Trigger,
+		&task.Action,
+		&task.Status,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.NextRun,
+		&task.Storage,
+		&task.Retention,
+		&task.Alerts,
+		&task.Heartbeat,
+		&task.OverdueSince,
+		&checkinToken,
+		&checkinStatus,
+		&checkin.StartedAt,
+		&checkin.LastPingAt,
+		&task.Version,
+		&task.Revision,
+		&createdBy,
+		&updatedBy,
+	)
+	task.Overdue = task.OverdueSince != nil
+	task.CreatedBy = createdBy.String
+	task.UpdatedBy = updatedBy.String
+	if checkinToken.Valid {
+		checkin.Token = checkinToken.String
+		checkin.Status = models.CheckinStatus(checkinStatus.String)
+		task.Checkin = &checkin
+	}
+	return task, err
+}
+
+// CreateTask saves a new task together with its first version and the audit
+// event recording its creation.
+func (r *Repository) CreateTask(task *models.Task, version *models.TaskVersion, event *models.AuditEvent) error {
 	query := `
-		INSERT INTO tasks (id, name, trigger, action, status, created_at, updated_at, next_run)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO tasks (id, name, type, trigger, action, status, created_at, updated_at, next_run, storage_policy, retention_policy, alert_rules, heartbeat_policy, checkin_token, checkin_status, version, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
 	`
-	_, err := r.db.Exec(query,
+	var checkinToken, checkinStatus interface{}
+	if task.Checkin != nil {
+		checkinToken = task.Checkin.Token
+		checkinStatus = task.Checkin.Status
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query,
 		task.ID,
 		task.Name,
+		task.Type,
 		task.Trigger,
 		task.Action,
 		task.Status,
 		task.CreatedAt,
 		task.UpdatedAt,
 		task.NextRun,
+		task.Storage,
+		task.Retention,
+		task.Alerts,
+		task.Heartbeat,
+		checkinToken,
+		checkinStatus,
+		task.Version,
+		nullableString(task.CreatedBy),
+		nullableString(task.UpdatedBy),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if err := insertTaskVersion(tx, version); err != nil {
+		return err
+	}
+
+	if err := insertAuditEvent(tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *Repository) GetTaskByID(id uuid.UUID) (*models.Task, error) {
-	task := &models.Task{}
-	query := `
-		SELECT id, name, trigger, action, status, created_at, updated_at, next_run
-		FROM tasks
-		WHERE id = $1
-	`
-	err := r.db.QueryRow(query, id).Scan(
-		&task.ID,
-		&task.Name,
-		&task.Trigger,
-		&task.Action,
-		&task.Status,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-		&task.NextRun,
-	)
+	query := "SELECT " + taskColumns + " FROM tasks WHERE id = $1"
+	task, err := scanTask(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("task not found")
 	}
-	return task, err
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
 }
 
 func (r *Repository) ListTasks(params models.ListTasksParams) ([]models.Task, int, error) {
@@ -84,6 +159,20 @@ func (r *Repository) ListTasks(params models.ListTasksParams) ([]models.Task, in
 		argCount++
 	}
 
+	if params.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("type = $%d", argCount))
+		args = append(args, params.Type)
+		argCount++
+	}
+
+	if params.Overdue != nil {
+		if *params.Overdue {
+			conditions = append(conditions, "overdue_since IS NOT NULL")
+		} else {
+			conditions = append(conditions, "overdue_since IS NULL")
+		}
+	}
+
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
@@ -99,12 +188,12 @@ func (r *Repository) ListTasks(params models.ListTasksParams) ([]models.Task, in
 
 	// Get paginated results
 	query := fmt.Sprintf(`
-		SELECT id, name, trigger, action, status, created_at, updated_at, next_run
+		SELECT %s
 		FROM tasks
 		%s
 		ORDER BY created_at DESC
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argCount, argCount+1)
+	`, taskColumns, whereClause, argCount, argCount+1)
 
 	args = append(args, params.Limit, offset)
 
@@ -116,17 +205,7 @@ func (r *Repository) ListTasks(params models.ListTasksParams) ([]models.Task, in
 
 	tasks := []models.Task{}
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Name,
-			&task.Trigger,
-			&task.Action,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.NextRun,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -136,40 +215,73 @@ func (r *Repository) ListTasks(params models.ListTasksParams) ([]models.Task, in
 	return tasks, total, nil
 }
 
-func (r *Repository) UpdateTask(task *models.Task) error {
-	query := `
-		UPDATE tasks
-		SET name = $1, trigger = $2, action = $3, status = $4, updated_at = $5, next_run = $6
-		WHERE id = $7
-	`
-	result, err := r.db.Exec(query,
+const updateTaskQuery = `
+	UPDATE tasks
+	SET name = $1, trigger = $2, action = $3, status = $4, updated_at = $5, next_run = $6, storage_policy = $7, retention_policy = $8, alert_rules = $9, heartbeat_policy = $10, updated_by = $11, revision = revision + 1
+	WHERE id = $12
+`
+
+// updateTaskArgs returns a task's values in updateTaskQuery order.
+func updateTaskArgs(task *models.Task) []interface{} {
+	return []interface{}{
 		task.Name,
 		task.Trigger,
 		task.Action,
 		task.Status,
 		task.UpdatedAt,
 		task.NextRun,
+		task.Storage,
+		task.Retention,
+		task.Alerts,
+		task.Heartbeat,
+		nullableString(task.UpdatedBy),
 		task.ID,
-	)
-	if err != nil {
-		return err
 	}
+}
 
+// SetTaskNextRun saves task.NextRun, leaving the task's other fields alone,
+// and updates task.Revision if it changed.
+func (r *Repository) SetTaskNextRun(task *models.Task) error {
+	query := `
+		UPDATE tasks SET next_run = $1, revision = revision + 1
+		WHERE id = $2 AND next_run IS DISTINCT FROM $1
+		RETURNING revision
+	`
+	err := r.db.QueryRow(query, task.NextRun, task.ID).Scan(&task.Revision)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
+}
+
+// CompleteTask marks a scheduled task completed and clears its next run. It
+// reports whether the task was still scheduled; a task paused or cancelled in
+// the meantime is left alone.
+func (r *Repository) CompleteTask(id uuid.UUID, at time.Time) (bool, error) {
+	query := `
+		UPDATE tasks SET status = $1, next_run = NULL, updated_at = $2, revision = revision + 1
+		WHERE id = $3 AND status = $4
+	`
+	result, err := r.db.Exec(query, models.StatusCompleted, at, id, models.StatusScheduled)
+	if err != nil {
+		return false, err
+	}
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return false, err
 	}
+	return rowsAffected > 0, nil
+}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("task not found")
+func (r *Repository) DeleteTask(id uuid.UUID, updatedBy string, event *models.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	return nil
-}
-
-func (r *Repository) DeleteTask(id uuid.UUID) error {
-	query := "UPDATE tasks SET status = $1, updated_at = $2 WHERE id = $3"
-	result, err := r.db.Exec(query, models.StatusCancelled, time.Now(), id)
+	query := "UPDATE tasks SET status = $1, updated_at = $2, updated_by = $3, revision = revision + 1 WHERE id = $4"
+	result, err := tx.Exec(query, models.StatusCancelled, time.Now(), nullableString(updatedBy), id)
 	if err != nil {
 		return err
 	}
@@ -183,12 +295,16 @@ func (r *Repository) DeleteTask(id uuid.UUID) error {
 		return fmt.Errorf("task not found")
 	}
 
-	return nil
+	if err := insertAuditEvent(tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *Repository) GetScheduledTasks() ([]models.Task, error) {
 	query := `
-		SELECT id, name, trigger, action, status, created_at, updated_at, next_run
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE status = $1
 		ORDER BY next_run ASC NULLS LAST
@@ -201,17 +317,7 @@ func (r *Repository) GetScheduledTasks() ([]models.Task, error) {
 
 	tasks := []models.Task{}
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID,
-			&task.Name,
-			&task.Trigger,
-			&task.Action,
-			&task.Status,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-			&task.NextRun,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -221,33 +327,354 @@ func (r *Repository) GetScheduledTasks() ([]models.Task, error) {
 	return tasks, nil
 }
 
+// GetHeartbeatTasks returns the scheduled tasks that have a heartbeat policy.
+// Paused, cancelled and completed tasks are not expected to run, so they are
+// never reported as overdue.
+func (r *Repository) GetHeartbeatTasks() ([]models.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE heartbeat_policy IS NOT NULL AND status = $1"
+	rows, err := r.db.Query(query, models.StatusScheduled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// SetTaskOverdue flags a task as overdue since the given time. A task that is
+// already flagged keeps its original time.
+func (r *Repository) SetTaskOverdue(id uuid.UUID, since time.Time) error {
+	_, err := r.db.Exec("UPDATE tasks SET overdue_since = COALESCE(overdue_since, $1) WHERE id = $2", since, id)
+	return err
+}
+
+func (r *Repository) ClearTaskOverdue(id uuid.UUID) error {
+	_, err := r.db.Exec("UPDATE tasks SET overdue_since = NULL WHERE id = $1 AND overdue_since IS NOT NULL", id)
+	return err
+}
+
 // TaskResult Repository Methods
 
-func (r *Repository) CreateTaskResult(result *models.TaskResult) error {
+const taskResultColumns = "id, task_id, run_at, status_code, success, response_headers, response_body, body_size, body_sha256, body_truncated, body_blob_key, error_message, error_type, duration_ms, request, timing, trace_id, created_at, task_version"
+
+const taskResultPlaceholders = "$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTaskResult(row rowScanner) (models.TaskResult, error) {
+	var result models.TaskResult
+	var responseHeaders sql.NullString
+	var bodySHA256 sql.NullString
+	var bodyBlobKey sql.NullString
+	var errorType sql.NullString
+	var request sql.NullString
+	var timing sql.NullString
+	var traceID sql.NullString
+	var taskVersion sql.NullInt64
+	err := row.Scan(
+		&result.ID,
+		&result.TaskID,
+		&result.RunAt,
+		&result.StatusCode,
+		&result.Success,
+		&responseHeaders,
+		&result.ResponseBody,
+		&result.BodySize,
+		&bodySHA256,
+		&result.BodyTruncated,
+		&bodyBlobKey,
+		&result.ErrorMessage,
+		&errorType,
+		&result.DurationMs,
+		&request,
+		&timing,
+		&traceID,
+		&result.CreatedAt,
+		&taskVersion,
+	)
+	if err != nil {
+		return result, err
+	}
+
+	// Handle nullable response headers
+	if responseHeaders.Valid {
+		result.ResponseHeaders = json.RawMessage(responseHeaders.String)
+	} else {
+		result.ResponseHeaders = json.RawMessage("null")
+	}
+
+	result.BodySHA256 = bodySHA256.String
+	result.BodyBlobKey = bodyBlobKey.String
+	result.TraceID = traceID.String
+	result.TaskVersion = int(taskVersion.Int64)
+
+	if request.Valid {
+		result.Request = json.RawMessage(request.String)
+	}
+
+	if timing.Valid {
+		result.Timing = &models.RequestTiming{}
+		if err := json.Unmarshal([]byte(timing.String), result.Timing); err != nil {
+			return result, err
+		}
+	}
+
+	if errorType.Valid {
+		result.ErrorType = models.ErrorType(errorType.String)
+	}
+
+	return result, nil
+}
+
+// nullableJSON converts an empty or "null" raw message into a SQL NULL.
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) > 0 && string(raw) != "null" {
+		return string(raw)
+	}
+	return nil
+}
+
+// nullableInt converts a zero int into a SQL NULL.
+func nullableInt(i int) interface{} {
+	if i == 0 {
+		return nil
+	}
+	return i
+}
+
+// nullableString converts an empty string into a SQL NULL.
+func nullableString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// CreateTaskResult records a run. The task's run counters are always updated,
+// and returned as this run left them; the result row itself is stored,
+// trimmed or skipped according to policy.
+func (r *Repository) CreateTaskResult(result *models.TaskResult, policy *models.StoragePolicy) (*models.TaskRunCounters, error) {
+	stored := policy.StoresResult(result.Success)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	counterQuery := `
+		INSERT INTO task_run_counters (task_id, run_count, success_count, failure_count, stored_count, total_duration_ms, last_run_at, last_success, consecutive_failures)
+		VALUES ($1, 1, $2, $3, $4, $5, $6, $7, $3)
+		ON CONFLICT (task_id) DO UPDATE SET
+			run_count = task_run_counters.run_count + 1,
+			success_count = task_run_counters.success_count + EXCLUDED.success_count,
+			failure_count = task_run_counters.failure_count + EXCLUDED.failure_count,
+			stored_count = task_run_counters.stored_count + EXCLUDED.stored_count,
+			total_duration_ms = task_run_counters.total_duration_ms + EXCLUDED.total_duration_ms,
+			last_run_at = GREATEST(task_run_counters.last_run_at, EXCLUDED.last_run_at),
+			last_success = CASE WHEN task_run_counters.last_run_at > EXCLUDED.last_run_at
+				THEN task_run_counters.last_success ELSE EXCLUDED.last_success END,
+			consecutive_failures = CASE
+				WHEN task_run_counters.last_run_at > EXCLUDED.last_run_at THEN task_run_counters.consecutive_failures
+				WHEN EXCLUDED.last_success THEN 0
+				ELSE task_run_counters.consecutive_failures + 1 END
+		RETURNING ` + taskRunCounterColumns + `
+	`
+	counters, err := scanTaskRunCounters(tx.QueryRow(counterQuery,
+		result.TaskID,
+		boolToInt(result.Success),
+		boolToInt(!result.Success),
+		boolToInt(stored),
+		result.DurationMs,
+		result.RunAt,
+		result.Success,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	hourlyQuery := `
+		INSERT INTO task_run_hourly (task_id, hour, run_count, success_count, failure_count, total_duration_ms)
+		VALUES ($1, date_trunc('hour', $2::timestamptz AT TIME ZONE 'UTC') AT TIME ZONE 'UTC', 1, $3, $4, $5)
+		ON CONFLICT (task_id, hour) DO UPDATE SET
+			run_count = task_run_hourly.run_count + 1,
+			success_count = task_run_hourly.success_count + EXCLUDED.success_count,
+			failure_count = task_run_hourly.failure_count + EXCLUDED.failure_count,
+			total_duration_ms = task_run_hourly.total_duration_ms + EXCLUDED.total_duration_ms
+	`
+	_, err = tx.Exec(hourlyQuery,
+		result.TaskID,
+		result.RunAt,
+		boolToInt(result.Success),
+		boolToInt(!result.Success),
+		result.DurationMs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any run means the task is no longer overdue
+	if _, err := tx.Exec("UPDATE tasks SET overdue_since = NULL WHERE id = $1 AND overdue_since IS NOT NULL", result.TaskID); err != nil {
+		return nil, err
+	}
+
+	if stored {
+		if !policy.StoresBody(result.Success) {
+			result.ResponseHeaders = nil
+			result.ResponseBody = ""
+			result.BodyBlobKey = ""
+		}
+		if err := insertTaskResult(tx, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
+
+func insertTaskResult(tx *Tx, result *models.TaskResult) error {
 	query := `
-		INSERT INTO task_results (id, task_id, run_at, status_code, success, response_headers, response_body, error_message, duration_ms, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO task_results (` + taskResultColumns + `)
+		VALUES (` + taskResultPlaceholders + `)
 	`
-	var responseHeaders interface{}
-	if len(result.ResponseHeaders) > 0 && string(result.ResponseHeaders) != "null" {
-		responseHeaders = string(result.ResponseHeaders)
-	} else {
-		responseHeaders = nil
+	_, err := tx.Exec(query, taskResultArgs(result)...)
+	return err
+}
+
+// taskResultArgs returns a result's values in taskResultColumns order.
+func taskResultArgs(result *models.TaskResult) []interface{} {
+	var timing interface{}
+	if result.Timing != nil {
+		timing = result.Timing
 	}
 
-	_, err := r.db.Exec(query,
+	return []interface{}{
 		result.ID,
 		result.TaskID,
 		result.RunAt,
 		result.StatusCode,
 		result.Success,
-		responseHeaders,
+		nullableJSON(result.ResponseHeaders),
 		result.ResponseBody,
+		result.BodySize,
+		nullableString(result.BodySHA256),
+		result.BodyTruncated,
+		nullableString(result.BodyBlobKey),
 		result.ErrorMessage,
+		nullableString(string(result.ErrorType)),
 		result.DurationMs,
+		nullableJSON(result.Request),
+		timing,
+		nullableString(result.TraceID),
 		result.CreatedAt,
+		nullableInt(result.TaskVersion),
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+const taskRunCounterColumns = "task_id, run_count, success_count, failure_count, stored_count, total_duration_ms, last_run_at, last_success, consecutive_failures"
+
+func scanTaskRunCounters(row rowScanner) (*models.TaskRunCounters, error) {
+	counters := &models.TaskRunCounters{}
+	err := row.Scan(
+		&counters.TaskID,
+		&counters.RunCount,
+		&counters.SuccessCount,
+		&counters.FailureCount,
+		&counters.StoredCount,
+		&counters.TotalDurationMs,
+		&counters.LastRunAt,
+		&counters.LastSuccess,
+		&counters.ConsecutiveFailures,
 	)
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
+
+// GetTaskRunCounters returns the run counters for a task, or nil if it has
+// never run.
+func (r *Repository) GetTaskRunCounters(taskID uuid.UUID) (*models.TaskRunCounters, error) {
+	query := "SELECT " + taskRunCounterColumns + " FROM task_run_counters WHERE task_id = $1"
+	counters, err := scanTaskRunCounters(r.db.QueryRow(query, taskID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
+
+// GetLastRunTimes returns when each of the given tasks last ran. Tasks that
+// have never run are left out.
+func (r *Repository) GetLastRunTimes(taskIDs []uuid.UUID) (map[uuid.UUID]time.Time, error) {
+	ids := make([]string, len(taskIDs))
+	for i, id := range taskIDs {
+		ids[i] = id.String()
+	}
+
+	query := "SELECT task_id, last_run_at FROM task_run_counters WHERE task_id = ANY($1::uuid[]) AND last_run_at IS NOT NULL"
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastRuns := make(map[uuid.UUID]time.Time, len(taskIDs))
+	for rows.Next() {
+		var id uuid.UUID
+		var lastRun time.Time
+		if err := rows.Scan(&id, &lastRun); err != nil {
+			return nil, err
+		}
+		lastRuns[id] = lastRun
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lastRuns, nil
+}
+
+// GetTaskResultByID returns a result. Passing its run_at lets Postgres read
+// only the partition holding it; otherwise every partition is searched.
+func (r *Repository) GetTaskResultByID(id uuid.UUID, runAt *time.Time) (*models.TaskResult, error) {
+	query := "SELECT " + taskResultColumns + " FROM task_results WHERE id = $1"
+	args := []interface{}{id}
+	if runAt != nil {
+		query += " AND run_at = $2"
+		args = append(args, *runAt)
+	}
+	result, err := scanTaskResult(r.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task result not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
 }
 
 func (r *Repository) GetTaskResults(taskID uuid.UUID, page, limit int) ([]models.TaskResult, int, error) {
@@ -270,7 +697,7 @@ func (r *Repository) GetTaskResults(taskID uuid.UUID, page, limit int) ([]models
 
 	// Get paginated results
 	query := `
-		SELECT id, task_id, run_at, status_code, success, response_headers, response_body, error_message, duration_ms, created_at
+		SELECT ` + taskResultColumns + `
 		FROM task_results
 		WHERE task_id = $1
 		ORDER BY run_at DESC
@@ -284,37 +711,19 @@ func (r *Repository) GetTaskResults(taskID uuid.UUID, page, limit int) ([]models
 
 	results := []models.TaskResult{}
 	for rows.Next() {
-		var result models.TaskResult
-		var responseHeaders sql.NullString
-		err := rows.Scan(
-			&result.ID,
-			&result.TaskID,
-			&result.RunAt,
-			&result.StatusCode,
-			&result.Success,
-			&responseHeaders,
-			&result.ResponseBody,
-			&result.ErrorMessage,
-			&result.DurationMs,
-			&result.CreatedAt,
-		)
+		result, err := scanTaskResult(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-		
-		// Handle nullable response headers
-		if responseHeaders.Valid {
-			result.ResponseHeaders = json.RawMessage(responseHeaders.String)
-		} else {
-			result.ResponseHeaders = json.RawMessage("null")
-		}
-		
 		results = append(results, result)
 	}
 
 	return results, total, nil
 }
 
+// ListAllResults returns a page of results, newest first, and their total.
+// With params.After set, the page starts after that result and the total
+// isn't counted, so deep pages cost no more than the first.
 func (r *Repository) ListAllResults(params models.ListResultsParams) ([]models.TaskResult, int, error) {
 	if params.Page == 0 {
 		params.Page = 1
@@ -353,25 +762,33 @@ func (r *Repository) ListAllResults(params models.ListResultsParams) ([]models.T
 		argCount++
 	}
 
+	var total int
+	if params.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(run_at, id) < ($%d, $%d)", argCount, argCount+1))
+		args = append(args, params.After.RunAt, params.After.ID)
+		argCount += 2
+		offset = 0
+	}
+
 	whereClause := ""
 	if len(conditions) > 0 {
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	var total int
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM task_results %s", whereClause)
-	err := r.db.QueryRow(countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, err
+	if params.After == nil {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM task_results %s", whereClause)
+		if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+			return nil, 0, err
+		}
 	}
 
 	query := fmt.Sprintf(`
-		SELECT id, task_id, run_at, status_code, success, response_headers, response_body, error_message, duration_ms, created_at
+		SELECT %s
 		FROM task_results
 		%s
-		ORDER BY run_at DESC
+		ORDER BY run_at DESC, id DESC
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argCount, argCount+1)
+	`, taskResultColumns, whereClause, argCount, argCount+1)
 
 	args = append(args, params.Limit, offset)
 
@@ -383,33 +800,12 @@ func (r *Repository) ListAllResults(params models.ListResultsParams) ([]models.T
 
 	results := []models.TaskResult{}
 	for rows.Next() {
-		var result models.TaskResult
-		var responseHeaders sql.NullString
-		err := rows.Scan(
-			&result.ID,
-			&result.TaskID,
-			&result.RunAt,
-			&result.StatusCode,
-			&result.Success,
-			&responseHeaders,
-			&result.ResponseBody,
-			&result.ErrorMessage,
-			&result.DurationMs,
-			&result.CreatedAt,
-		)
+		result, err := scanTaskResult(rows)
 		if err != nil {
 			return nil, 0, err
 		}
-		
-		// Handle nullable response headers
-		if responseHeaders.Valid {
-			result.ResponseHeaders = json.RawMessage(responseHeaders.String)
-		} else {
-			result.ResponseHeaders = json.RawMessage("null")
-		}
-		
 		results = append(results, result)
 	}
 
 	return results, total, nil
-}
\ No newline at end of file
+}
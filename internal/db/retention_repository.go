@@ -0,0 +1,111 @@
+package db
+
+import (
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Retention Repository Methods
+
+// ListTaskRetentionPolicies returns the retention override of every task that
+// has stored results. Tasks without an override map to nil.
+func (r *Repository) ListTaskRetentionPolicies() (map[uuid.UUID]*models.RetentionPolicy, error) {
+	query := `
+		SELECT t.id, t.retention_policy
+		FROM tasks t
+		WHERE EXISTS (SELECT 1 FROM task_results r WHERE r.task_id = t.id)
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := make(map[uuid.UUID]*models.RetentionPolicy)
+	for rows.Next() {
+		var id uuid.UUID
+		var policy *models.RetentionPolicy
+		if err := rows.Scan(&id, &policy); err != nil {
+			return nil, err
+		}
+		policies[id] = policy
+	}
+	return policies, rows.Err()
+}
+
+// PruneTaskResultsBefore deletes up to limit of a task's results that ran
+// before cutoff, oldest first.
+func (r *Repository) PruneTaskResultsBefore(taskID uuid.UUID, cutoff time.Time, limit int) (int, []string, error) {
+	return r.pruneTaskResults(`
+		SELECT id FROM task_results
+		WHERE task_id = $1 AND run_at < $2
+		ORDER BY run_at
+		LIMIT $3
+	`, taskID, cutoff, limit)
+}
+
+// PruneTaskResultsBeyond deletes up to limit of a task's results that are
+// older than its keep most recent ones, oldest first.
+func (r *Repository) PruneTaskResultsBeyond(taskID uuid.UUID, keep, limit int) (int, []string, error) {
+	return r.pruneTaskResults(`
+		SELECT id FROM (
+			SELECT id, run_at FROM task_results
+			WHERE task_id = $1
+			ORDER BY run_at DESC
+			OFFSET $2
+		) extra
+		ORDER BY run_at
+		LIMIT $3
+	`, taskID, keep, limit)
+}
+
+// pruneTaskResults deletes the results selected by idQuery and folds them into
+// the daily rollups in the same statement, so statistics survive the delete.
+// It returns the number of deleted rows and their body blob keys.
+func (r *Repository) pruneTaskResults(idQuery string, args ...interface{}) (int, []string, error) {
+	query := `
+		WITH doomed AS (` + idQuery + `),
+		deleted AS (
+			DELETE FROM task_results
+			WHERE id IN (SELECT id FROM doomed)
+			RETURNING task_id, run_at, success, duration_ms, body_blob_key
+		),
+		rollup AS (` + dailyRollupInsert("deleted") + `)
+		SELECT COUNT(*), COALESCE(array_agg(body_blob_key) FILTER (WHERE body_blob_key IS NOT NULL), '{}')
+		FROM deleted
+	`
+	var deleted int
+	var blobKeys []string
+	if err := r.db.QueryRow(query, args...).Scan(&deleted, pq.Array(&blobKeys)); err != nil {
+		return 0, nil, err
+	}
+	return deleted, blobKeys, nil
+}
+
+// dailyRollupInsert returns a statement that adds the results in source to
+// task_result_daily.
+func dailyRollupInsert(source string) string {
+	return `
+		INSERT INTO task_result_daily (task_id, day, run_count, success_count, failure_count, total_duration_ms, min_duration_ms, max_duration_ms)
+		SELECT task_id,
+		       (run_at AT TIME ZONE 'UTC')::date,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE success),
+		       COUNT(*) FILTER (WHERE NOT success),
+		       SUM(duration_ms),
+		       MIN(duration_ms),
+		       MAX(duration_ms)
+		FROM ` + source + `
+		GROUP BY 1, 2
+		ON CONFLICT (task_id, day) DO UPDATE SET
+			run_count = task_result_daily.run_count + EXCLUDED.run_count,
+			success_count = task_result_daily.success_count + EXCLUDED.success_count,
+			failure_count = task_result_daily.failure_count + EXCLUDED.failure_count,
+			total_duration_ms = task_result_daily.total_duration_ms + EXCLUDED.total_duration_ms,
+			min_duration_ms = LEAST(task_result_daily.min_duration_ms, EXCLUDED.min_duration_ms),
+			max_duration_ms = GREATEST(task_result_daily.max_duration_ms, EXCLUDED.max_duration_ms)
+	`
+}
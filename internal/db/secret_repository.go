@@ -0,0 +1,129 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// Secret Repository Methods
+
+func (r *Repository) CreateSecret(secret *models.Secret) error {
+	query := `
+		INSERT INTO secrets (id, name, key_id, ciphertext, nonce, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(query,
+		secret.ID,
+		secret.Name,
+		secret.KeyID,
+		secret.Ciphertext,
+		secret.Nonce,
+		secret.CreatedAt,
+		secret.UpdatedAt,
+	)
+	return err
+}
+
+func (r *Repository) GetSecretByName(name string) (*models.Secret, error) {
+	secret := &models.Secret{}
+	query := `
+		SELECT id, name, key_id, ciphertext, nonce, created_at, updated_at
+		FROM secrets
+		WHERE name = $1
+	`
+	err := r.db.QueryRow(query, name).Scan(
+		&secret.ID,
+		&secret.Name,
+		&secret.KeyID,
+		&secret.Ciphertext,
+		&secret.Nonce,
+		&secret.CreatedAt,
+		&secret.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("secret not found")
+	}
+	return secret, err
+}
+
+func (r *Repository) ListSecrets() ([]models.Secret, error) {
+	query := `
+		SELECT id, name, key_id, ciphertext, nonce, created_at, updated_at
+		FROM secrets
+		ORDER BY name ASC
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	secrets := []models.Secret{}
+	for rows.Next() {
+		var secret models.Secret
+		err := rows.Scan(
+			&secret.ID,
+			&secret.Name,
+			&secret.KeyID,
+			&secret.Ciphertext,
+			&secret.Nonce,
+			&secret.CreatedAt,
+			&secret.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
+
+func (r *Repository) UpdateSecret(secret *models.Secret) error {
+	query := `
+		UPDATE secrets
+		SET key_id = $1, ciphertext = $2, nonce = $3, updated_at = $4
+		WHERE name = $5
+	`
+	result, err := r.db.Exec(query,
+		secret.KeyID,
+		secret.Ciphertext,
+		secret.Nonce,
+		secret.UpdatedAt,
+		secret.Name,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("secret not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteSecret(name string) error {
+	result, err := r.db.Exec("DELETE FROM secrets WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("secret not found")
+	}
+
+	return nil
+}
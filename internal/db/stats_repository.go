@@ -0,0 +1,143 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+// Stats Repository Methods
+
+// GetExecutionStats aggregates runs in [from, to), for one task or, when
+// taskID is nil, for all tasks. Run counts, success rate, average duration
+// and buckets come from the hourly run counts, which include every run; from
+// and to must therefore fall on the hour and interval must be whole hours.
+// Duration percentiles and status codes need the individual runs and are
+// computed over stored results only. Empty buckets are included.
+func (r *Repository) GetExecutionStats(taskID *uuid.UUID, from, to time.Time, interval time.Duration) (*models.ExecutionStats, error) {
+	stats := &models.ExecutionStats{
+		TaskID:      taskID,
+		From:        from,
+		To:          to,
+		Interval:    interval.String(),
+		StatusCodes: []models.StatusCodeCount{},
+	}
+
+	args := []interface{}{from, to}
+	hourlyWhere := "hour >= $1::timestamptz AND hour < $2::timestamptz"
+	where := "run_at >= $1::timestamptz AND run_at < $2::timestamptz"
+	if taskID != nil {
+		hourlyWhere += " AND task_id = $3"
+		where += " AND task_id = $3"
+		args = append(args, *taskID)
+	}
+
+	var totalDurationMs int64
+	countQuery := `
+		SELECT COALESCE(SUM(run_count), 0),
+		       COALESCE(SUM(success_count), 0),
+		       COALESCE(SUM(failure_count), 0),
+		       COALESCE(SUM(total_duration_ms), 0)
+		FROM task_run_hourly
+		WHERE ` + hourlyWhere
+	err := r.db.QueryRow(countQuery, args...).Scan(
+		&stats.RunCount,
+		&stats.SuccessCount,
+		&stats.FailureCount,
+		&totalDurationMs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if stats.RunCount > 0 {
+		stats.SuccessRate = float64(stats.SuccessCount) / float64(stats.RunCount)
+		stats.DurationMs.Avg = float64(totalDurationMs) / float64(stats.RunCount)
+	}
+
+	percentileQuery := `
+		SELECT COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_ms), 0),
+		       COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY duration_ms), 0),
+		       COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY duration_ms), 0)
+		FROM task_results
+		WHERE ` + where
+	err = r.db.QueryRow(percentileQuery, args...).Scan(
+		&stats.DurationMs.P50,
+		&stats.DurationMs.P90,
+		&stats.DurationMs.P99,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	statusQuery := `
+		SELECT status_code, COUNT(*)
+		FROM task_results
+		WHERE ` + where + `
+		GROUP BY status_code
+		ORDER BY status_code
+	`
+	rows, err := r.db.Query(statusQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var count models.StatusCodeCount
+		if err := rows.Scan(&count.StatusCode, &count.Count); err != nil {
+			return nil, err
+		}
+		stats.StatusCodes = append(stats.StatusCodes, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if stats.Buckets, err = r.statsBuckets(hourlyWhere, args, from, to, interval); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *Repository) statsBuckets(where string, args []interface{}, from, to time.Time, interval time.Duration) ([]models.StatsBucket, error) {
+	query := fmt.Sprintf(`
+		SELECT date_bin($%d::interval, hour, $1::timestamptz) AS bucket,
+		       SUM(run_count),
+		       SUM(failure_count)
+		FROM task_run_hourly
+		WHERE %s
+		GROUP BY bucket
+	`, len(args)+1, where)
+	args = append(args, fmt.Sprintf("%d microseconds", interval.Microseconds()))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]models.StatsBucket)
+	for rows.Next() {
+		var bucket models.StatsBucket
+		if err := rows.Scan(&bucket.Start, &bucket.RunCount, &bucket.FailureCount); err != nil {
+			return nil, err
+		}
+		counts[bucket.Start.UnixMicro()] = bucket
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	buckets := []models.StatsBucket{}
+	for start := from; start.Before(to); start = start.Add(interval) {
+		bucket, ok := counts[start.UnixMicro()]
+		if !ok {
+			bucket = models.StatsBucket{}
+		}
+		bucket.Start = start
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
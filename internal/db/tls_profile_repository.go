@@ -0,0 +1,131 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// TLSProfile Repository Methods
+
+const tlsProfileColumns = "id, name, client_cert, client_key_secret, ca_bundle, server_name, min_version, insecure_skip_verify, created_at, updated_at"
+
+func scanTLSProfile(row rowScanner) (models.TLSProfile, error) {
+	var profile models.TLSProfile
+	err := row.Scan(
+		&profile.ID,
+		&profile.Name,
+		&profile.ClientCert,
+		&profile.ClientKeySecret,
+		&profile.CABundle,
+		&profile.ServerName,
+		&profile.MinVersion,
+		&profile.InsecureSkipVerify,
+		&profile.CreatedAt,
+		&profile.UpdatedAt,
+	)
+	return profile, err
+}
+
+func (r *Repository) CreateTLSProfile(profile *models.TLSProfile) error {
+	query := `
+		INSERT INTO tls_profiles (id, name, client_cert, client_key_secret, ca_bundle, server_name, min_version, insecure_skip_verify, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := r.db.Exec(query,
+		profile.ID,
+		profile.Name,
+		profile.ClientCert,
+		profile.ClientKeySecret,
+		profile.CABundle,
+		profile.ServerName,
+		profile.MinVersion,
+		profile.InsecureSkipVerify,
+		profile.CreatedAt,
+		profile.UpdatedAt,
+	)
+	return err
+}
+
+func (r *Repository) GetTLSProfileByName(name string) (*models.TLSProfile, error) {
+	query := "SELECT " + tlsProfileColumns + " FROM tls_profiles WHERE name = $1"
+	profile, err := scanTLSProfile(r.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("tls profile not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *Repository) ListTLSProfiles() ([]models.TLSProfile, error) {
+	query := "SELECT " + tlsProfileColumns + " FROM tls_profiles ORDER BY name ASC"
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := []models.TLSProfile{}
+	for rows.Next() {
+		profile, err := scanTLSProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
+}
+
+func (r *Repository) UpdateTLSProfile(profile *models.TLSProfile) error {
+	query := `
+		UPDATE tls_profiles
+		SET client_cert = $1, client_key_secret = $2, ca_bundle = $3, server_name = $4, min_version = $5, insecure_skip_verify = $6, updated_at = $7
+		WHERE name = $8
+	`
+	result, err := r.db.Exec(query,
+		profile.ClientCert,
+		profile.ClientKeySecret,
+		profile.CABundle,
+		profile.ServerName,
+		profile.MinVersion,
+		profile.InsecureSkipVerify,
+		profile.UpdatedAt,
+		profile.Name,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("tls profile not found")
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteTLSProfile(name string) error {
+	result, err := r.db.Exec("DELETE FROM tls_profiles WHERE name = $1", name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("tls profile not found")
+	}
+
+	return nil
+}
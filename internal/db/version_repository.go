@@ -0,0 +1,138 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+// Task Version Repository Methods
+
+const taskVersionColumns = "task_id, version, trigger, action, created_at, created_by, restored_from"
+
+func scanTaskVersion(row rowScanner) (models.TaskVersion, error) {
+	var version models.TaskVersion
+	err := row.Scan(
+		&version.TaskID,
+		&version.Version,
+		&version.Trigger,
+		&version.Action,
+		&version.CreatedAt,
+		&version.CreatedBy,
+		&version.RestoredFrom,
+	)
+	return version, err
+}
+
+func insertTaskVersion(tx *Tx, version *models.TaskVersion) error {
+	query := `
+		INSERT INTO task_versions (` + taskVersionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := tx.Exec(query,
+		version.TaskID,
+		version.Version,
+		version.Trigger,
+		version.Action,
+		version.CreatedAt,
+		version.CreatedBy,
+		version.RestoredFrom,
+	)
+	return err
+}
+
+// UpdateTaskWithAudit saves a task's fields together with the audit event
+// describing the change. When version is set, it is saved as a new version
+// of the task's trigger and action and becomes the task's current version.
+// The update only applies while the stored task is still at expectedRevision,
+// the revision it was read at, so concurrent changes to the task, the
+// scheduler's included, cannot overwrite each other; otherwise it fails with
+// "task version conflict". task.Revision is set to the new revision.
+func (r *Repository) UpdateTaskWithAudit(task *models.Task, expectedRevision int64, version *models.TaskVersion, event *models.AuditEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := updateTaskQuery + "	AND revision = $13\n	RETURNING revision\n"
+	err = tx.QueryRow(query, append(updateTaskArgs(task), expectedRevision)...).Scan(&task.Revision)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == sql.ErrNoRows {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1)", task.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("task version conflict")
+		}
+		return fmt.Errorf("task not found")
+	}
+
+	if version != nil {
+		if err := insertTaskVersion(tx, version); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("UPDATE tasks SET version = $1 WHERE id = $2", version.Version, task.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := insertAuditEvent(tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListTaskVersions returns a page of a task's versions, newest first, and
+// the total number of versions.
+func (r *Repository) ListTaskVersions(taskID uuid.UUID, params models.ListVersionsParams) ([]models.TaskVersion, int, error) {
+	if params.Page == 0 {
+		params.Page = 1
+	}
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM task_versions WHERE task_id = $1", taskID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + taskVersionColumns + " FROM task_versions WHERE task_id = $1 ORDER BY version DESC LIMIT $2 OFFSET $3"
+	rows, err := r.db.Query(query, taskID, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	versions := []models.TaskVersion{}
+	for rows.Next() {
+		version, err := scanTaskVersion(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, total, rows.Err()
+}
+
+func (r *Repository) GetTaskVersion(taskID uuid.UUID, number int) (*models.TaskVersion, error) {
+	query := "SELECT " + taskVersionColumns + " FROM task_versions WHERE task_id = $1 AND version = $2"
+	version, err := scanTaskVersion(r.db.QueryRow(query, taskID, number))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("task version not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
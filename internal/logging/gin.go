@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID on requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// redactedPathValue replaces secret route parameters in logged paths.
+const redactedPathValue = "[REDACTED]"
+
+// probePaths are polled constantly, so successful requests to them are only
+// logged at debug level.
+var probePaths = map[string]bool{
+	"/health":  true,
+	"/livez":   true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// GinMiddleware assigns every request an ID, taken from the X-Request-ID
+// header when the client sends one, and echoes it on the response. Records
+// logged with the request's context carry the ID. Each request is logged
+// once it completes.
+func GinMiddleware() gin.HandlerFunc {
+	logger := For("api")
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(WithAttrs(c.Request.Context(), "request_id", requestID))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case probePaths[c.Request.URL.Path] && status < 400:
+			level = slog.LevelDebug
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		logger.LogAttrs(c.Request.Context(), level, "Request completed", attrs...)
+	}
+}
+
+// RedactPathParams hides the values of the named route parameters in the
+// request path, so that credentials carried in URLs, such as check-in ping
+// tokens, are neither logged nor recorded on spans. Handlers still read the
+// values with c.Param. It must be registered before any middleware that
+// records the path.
+func RedactPathParams(names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, name := range names {
+			if value := c.Param(name); value != "" {
+				path = strings.Replace(path, "/"+value, "/"+redactedPathValue, 1)
+			}
+		}
+		if path != c.Request.URL.Path {
+			c.Request.URL.Path = path
+			c.Request.URL.RawPath = ""
+			c.Request.RequestURI = c.Request.URL.RequestURI()
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,256 @@
+// Package logging configures structured logging with log/slog. Each package
+// logs through its own logger so that levels can be set per package, and
+// changed while the server runs.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+)
+
+// base is the handler that formats and writes records. Loggers resolve it on
+// use, so loggers created before Init pick up its configuration.
+type base struct {
+	slog.Handler
+}
+
+var root atomic.Pointer[base]
+
+var (
+	mu            sync.RWMutex
+	defaultLevel  = slog.LevelInfo
+	packageLevels = map[string]slog.Level{}
+	packages      = map[string]bool{}
+)
+
+func init() {
+	root.Store(&base{newHandler(os.Stderr, "json")})
+}
+
+func newHandler(w io.Writer, format string) slog.Handler {
+	// Levels are checked per package before records reach the handler
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if format == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+// Init applies the configured format and levels and makes the "app" logger
+// the slog default, which also routes the standard log package through it.
+func Init(cfg config.LogConfig) error {
+	if cfg.Format != "json" && cfg.Format != "text" {
+		return fmt.Errorf("invalid log format %q: must be json or text", cfg.Format)
+	}
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	overrides := map[string]slog.Level{}
+	for _, entry := range cfg.Packages {
+		pkg, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			return fmt.Errorf("invalid package log level %q: expected package=level", entry)
+		}
+		pkgLevel, err := parseLevel(name)
+		if err != nil {
+			return err
+		}
+		overrides[strings.TrimSpace(pkg)] = pkgLevel
+	}
+
+	mu.Lock()
+	defaultLevel = level
+	packageLevels = overrides
+	mu.Unlock()
+
+	root.Store(&base{newHandler(os.Stderr, cfg.Format)})
+	slog.SetDefault(For("app"))
+	return nil
+}
+
+// For returns the logger for a package. Records carry the package name as
+// "component" and are filtered by that package's level.
+func For(pkg string) *slog.Logger {
+	mu.Lock()
+	packages[pkg] = true
+	mu.Unlock()
+
+	return slog.New(&handler{
+		pkg: pkg,
+		ops: []func(slog.Handler) slog.Handler{func(h slog.Handler) slog.Handler {
+			return h.WithAttrs([]slog.Attr{slog.String("component", pkg)})
+		}},
+	})
+}
+
+// Levels reports the default level and the effective level of every package
+// that has a logger or an override.
+func Levels() models.LogLevels {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	levels := models.LogLevels{
+		Default:   levelName(defaultLevel),
+		Packages:  map[string]string{},
+		Overrides: []string{},
+	}
+	for pkg := range packages {
+		levels.Packages[pkg] = levelName(defaultLevel)
+	}
+	for pkg, level := range packageLevels {
+		levels.Packages[pkg] = levelName(level)
+		levels.Overrides = append(levels.Overrides, pkg)
+	}
+	sort.Strings(levels.Overrides)
+	return levels
+}
+
+// SetLevel changes the level of pkg, or the default level when pkg is empty
+// or "default". An empty level removes a package's override.
+func SetLevel(pkg, name string) error {
+	if pkg == "" || pkg == "default" {
+		level, err := parseLevel(name)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defaultLevel = level
+		mu.Unlock()
+		return nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !packages[pkg] {
+		if _, ok := packageLevels[pkg]; !ok {
+			return fmt.Errorf("unknown logging package %q", pkg)
+		}
+	}
+	if name == "" {
+		delete(packageLevels, pkg)
+		return nil
+	}
+	level, err := parseLevel(name)
+	if err != nil {
+		return err
+	}
+	packageLevels[pkg] = level
+	return nil
+}
+
+func levelFor(pkg string) slog.Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	if level, ok := packageLevels[pkg]; ok {
+		return level
+	}
+	return defaultLevel
+}
+
+func parseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", name)
+	}
+}
+
+func levelName(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+type attrsKey struct{}
+
+// WithAttrs returns a copy of ctx whose log records carry args, given as
+// alternating keys and values or slog.Attr, in addition to any already set.
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	attrs := append([]slog.Attr{}, attrsFrom(ctx)...)
+	record := slog.Record{}
+	record.Add(args...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey{}, attrs)
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// handler filters records by package level, adds context attributes and the
+// trace ID, and passes them to the current base handler.
+type handler struct {
+	pkg   string
+	ops   []func(slog.Handler) slog.Handler
+	cache atomic.Pointer[resolved]
+}
+
+type resolved struct {
+	base    *base
+	handler slog.Handler
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= levelFor(h.pkg)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(attrsFrom(ctx)...)
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		r.AddAttrs(slog.String("trace_id", traceID))
+	}
+	return h.resolve().Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *handler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &handler{pkg: h.pkg, ops: append(ops, op)}
+}
+
+// resolve applies the logger's attributes and groups to the current base
+// handler, reusing the result until the base changes.
+func (h *handler) resolve() slog.Handler {
+	current := root.Load()
+	if cached := h.cache.Load(); cached != nil && cached.base == current {
+		return cached.handler
+	}
+	next := current.Handler
+	for _, op := range h.ops {
+		next = op(next)
+	}
+	h.cache.Store(&resolved{base: current, handler: next})
+	return next
+}
@@ -0,0 +1,117 @@
+// Package metrics defines the Prometheus metrics exported on /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "task_scheduler"
+
+var (
+	executionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "executions_total",
+		Help:      "Task executions by task, outcome and HTTP status code (0 when no response was received).",
+	}, []string{"task_id", "outcome", "status_code"})
+
+	executionDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "execution_duration_seconds",
+		Help:      "Task execution duration, from start to stored result.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"task_id", "outcome"})
+
+	schedulingLag = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scheduling_lag_seconds",
+		Help:      "Delay between a task's intended fire time and the start of its execution.",
+		Buckets:   []float64{.001, .005, .01, .05, .1, .5, 1, 5, 10, 30, 60},
+	}, []string{"trigger"})
+
+	executionsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "executions_in_flight",
+		Help:      "Task executions currently running.",
+	})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database statement latency by repository operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation"})
+
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "API requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "API request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// Handler serves all registered metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterCronEntries exports the number of cron entries reported by count.
+func RegisterCronEntries(count func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cron_entries",
+		Help:      "Cron entries registered with the scheduler.",
+	}, func() float64 {
+		return float64(count())
+	})
+}
+
+// ExecutionStarted records the scheduling lag of an execution and marks it in
+// flight. The returned function marks it finished.
+func ExecutionStarted(trigger string, scheduledAt, startedAt time.Time) func() {
+	if lag := startedAt.Sub(scheduledAt); lag >= 0 {
+		schedulingLag.WithLabelValues(trigger).Observe(lag.Seconds())
+	}
+	executionsInFlight.Inc()
+	return executionsInFlight.Dec
+}
+
+// ObserveExecution records the outcome and duration of an execution.
+func ObserveExecution(taskID, outcome string, statusCode int, duration time.Duration) {
+	executionsTotal.WithLabelValues(taskID, outcome, strconv.Itoa(statusCode)).Inc()
+	executionDuration.WithLabelValues(taskID, outcome).Observe(duration.Seconds())
+}
+
+// ObserveDBQuery records the latency of a statement started at start.
+func ObserveDBQuery(operation string, start time.Time) {
+	dbQueryDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// GinMiddleware records request counts and latency for every API route.
+// Requests that match no route are grouped under "unmatched".
+func GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		httpRequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		httpRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
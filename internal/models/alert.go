@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AlertType string
+
+const (
+	// AlertConsecutiveFailures fires once a task has failed Failures times in
+	// a row and resolves on its next success.
+	AlertConsecutiveFailures AlertType = "consecutive_failures"
+	// AlertRecovery fires when a task succeeds again after failing. If the
+	// task has a consecutive_failures rule, only once that rule has fired.
+	AlertRecovery AlertType = "recovery"
+	// AlertLatency fires when a run takes longer than LatencyMs and resolves
+	// when a run completes within it.
+	AlertLatency AlertType = "latency"
+	// AlertOverdue fires when a task with a heartbeat policy has not run by
+	// its expected time plus the grace period, and resolves when it runs.
+	AlertOverdue AlertType = "overdue"
+)
+
+// HeartbeatPolicy sets when a task is expected to run. A run is expected
+// IntervalSeconds after the previous one or, when that is zero, at the next
+// time the trigger fires. The task is overdue once GraceSeconds have passed
+// without a run after that.
+type HeartbeatPolicy struct {
+	IntervalSeconds int64 `json:"interval_seconds,omitempty"`
+	GraceSeconds    int64 `json:"grace_seconds"`
+}
+
+func (p *HeartbeatPolicy) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal HeartbeatPolicy value")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+func (p HeartbeatPolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// AlertRule notifies Channels when a task's runs meet a condition. While a
+// rule keeps firing it is not notified again, unless RepeatAfterMinutes is set.
+type AlertRule struct {
+	Type               AlertType `json:"type"`
+	Channels           []string  `json:"channels"`
+	Failures           int       `json:"failures,omitempty"`
+	LatencyMs          int64     `json:"latency_ms,omitempty"`
+	RepeatAfterMinutes int       `json:"repeat_after_minutes,omitempty"`
+}
+
+// AlertRules are the alert rules of a task, at most one per type.
+type AlertRules []AlertRule
+
+// Rule returns the rule of the given type, or nil.
+func (r AlertRules) Rule(alertType AlertType) *AlertRule {
+	for i := range r {
+		if r[i].Type == alertType {
+			return &r[i]
+		}
+	}
+	return nil
+}
+
+func (r *AlertRules) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal AlertRules value")
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+func (r AlertRules) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// AlertState tracks whether a task's rule is firing and when it last notified.
+type AlertState struct {
+	TaskID         uuid.UUID  `json:"-" db:"task_id"`
+	RuleType       AlertType  `json:"rule_type" db:"rule_type"`
+	Firing         bool       `json:"firing" db:"firing"`
+	FiredAt        *time.Time `json:"fired_at,omitempty" db:"fired_at"`
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty" db:"last_notified_at"`
+}
+
+// Notification is the message sent to channels when an alert changes state.
+// Run fields are empty for overdue and test notifications.
+type Notification struct {
+	Event      AlertEvent `json:"event"`
+	RuleType   AlertType  `json:"rule_type,omitempty"`
+	TaskID     uuid.UUID  `json:"task_id"`
+	TaskName   string     `json:"task_name"`
+	ResultID   *uuid.UUID `json:"result_id,omitempty"`
+	Summary    string     `json:"summary"`
+	StatusCode int        `json:"status_code,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	DurationMs int64      `json:"duration_ms,omitempty"`
+	RunAt      *time.Time `json:"run_at,omitempty"`
+	ExpectedBy *time.Time `json:"expected_by,omitempty"`
+	TraceID    string     `json:"trace_id,omitempty"`
+}
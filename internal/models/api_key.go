@@ -0,0 +1,85 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APIScope string
+
+const (
+	ScopeTasksRead   APIScope = "tasks:read"
+	ScopeTasksWrite  APIScope = "tasks:write"
+	ScopeResultsRead APIScope = "results:read"
+	// ScopeAdmin grants every other scope, and access to settings such as
+	// secrets, profiles, channels and API keys
+	ScopeAdmin APIScope = "admin"
+)
+
+type APIScopes []APIScope
+
+func (s *APIScopes) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal APIScopes value")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+func (s APIScopes) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(s)
+}
+
+// Has reports whether the scopes grant scope.
+func (s APIScopes) Has(scope APIScope) bool {
+	for _, granted := range s {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey authenticates API requests. Only a hash of the key is stored; Prefix
+// is its first characters, to tell keys apart.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	Scopes     APIScopes  `json:"scopes" db:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	CreatedBy  string     `json:"created_by" db:"created_by"`
+	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// CreatedAPIKey is returned once, when a key is created; Key is not stored.
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=255"`
+	Scopes    APIScopes  `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write results:read admin"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// UpdateAPIKeyRequest changes the fields that are set. ClearExpiresAt removes
+// the key's expiry so it never expires; it can't be combined with ExpiresAt.
+type UpdateAPIKeyRequest struct {
+	Scopes         *APIScopes `json:"scopes,omitempty" binding:"omitempty,min=1,dive,oneof=tasks:read tasks:write results:read admin"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	ClearExpiresAt bool       `json:"clear_expires_at,omitempty" binding:"excluded_with=ExpiresAt"`
+}
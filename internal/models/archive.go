@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskMonth identifies one task's results for one calendar month (UTC).
+type TaskMonth struct {
+	TaskID uuid.UUID `json:"task_id"`
+	Month  time.Time `json:"month"`
+}
+
+// ArchiveManifest lists the archive files written for one task and month.
+type ArchiveManifest struct {
+	TaskID uuid.UUID     `json:"task_id"`
+	Month  string        `json:"month"`
+	Files  []ArchiveFile `json:"files"`
+}
+
+// ArchiveFile is a gzip-compressed NDJSON file of results with run_at in
+// [From, To).
+type ArchiveFile struct {
+	Name      string    `json:"name"`
+	SHA256    string    `json:"sha256"`
+	Results   int       `json:"results"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	CreatedAt time.Time `json:"created_at"`
+}
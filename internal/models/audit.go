@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditPause  AuditAction = "pause"
+	AuditResume AuditAction = "resume"
+	AuditCancel AuditAction = "cancel"
+	// AuditRestore is recorded when a task is rolled back to an earlier version
+	AuditRestore AuditAction = "restore"
+)
+
+// AuditChange is a field's JSON value before and after a mutation. Before is
+// empty for fields set on create.
+type AuditChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditChanges maps each changed task field to its before and after values.
+type AuditChanges map[string]AuditChange
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal AuditChanges value")
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// AuditEvent records who mutated a task, when, from where, and what changed.
+// Events are append-only.
+type AuditEvent struct {
+	ID         uuid.UUID    `json:"id" db:"id"`
+	OccurredAt time.Time    `json:"occurred_at" db:"occurred_at"`
+	Actor      string       `json:"actor" db:"actor"`
+	SourceIP   string       `json:"source_ip,omitempty" db:"source_ip"`
+	Action     AuditAction  `json:"action" db:"action"`
+	TaskID     uuid.UUID    `json:"task_id" db:"task_id"`
+	Changes    AuditChanges `json:"changes" db:"changes"`
+}
+
+type ListAuditParams struct {
+	Page     int         `form:"page"`
+	Limit    int         `form:"limit" binding:"max=100"`
+	TaskID   *uuid.UUID  `form:"task_id"`
+	Actor    string      `form:"actor"`
+	Action   AuditAction `form:"action"`
+	DateFrom *time.Time  `form:"date_from"`
+	DateTo   *time.Time  `form:"date_to"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthProfile holds OAuth2 client-credentials settings that tasks reference
+// by name. The client secret is the name of an entry in the secrets store.
+type AuthProfile struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	Name             string    `json:"name" db:"name"`
+	TokenURL         string    `json:"token_url" db:"token_url"`
+	ClientID         string    `json:"client_id" db:"client_id"`
+	ClientSecretName string    `json:"client_secret_name" db:"client_secret_name"`
+	Scopes           []string  `json:"scopes,omitempty" db:"scopes"`
+	Audience         string    `json:"audience,omitempty" db:"audience"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateAuthProfileRequest struct {
+	Name             string   `json:"name" binding:"required,max=255"`
+	TokenURL         string   `json:"token_url" binding:"required,url"`
+	ClientID         string   `json:"client_id" binding:"required"`
+	ClientSecretName string   `json:"client_secret_name" binding:"required"`
+	Scopes           []string `json:"scopes,omitempty"`
+	Audience         string   `json:"audience,omitempty"`
+}
+
+type UpdateAuthProfileRequest struct {
+	TokenURL         *string   `json:"token_url,omitempty" binding:"omitempty,url"`
+	ClientID         *string   `json:"client_id,omitempty"`
+	ClientSecretName *string   `json:"client_secret_name,omitempty"`
+	Scopes           *[]string `json:"scopes,omitempty"`
+	Audience         *string   `json:"audience,omitempty"`
+}
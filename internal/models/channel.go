@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChannelType string
+
+const (
+	ChannelWebhook ChannelType = "webhook"
+	ChannelSlack   ChannelType = "slack"
+	ChannelEmail   ChannelType = "email"
+)
+
+// ChannelConfig holds the settings of a notification channel. Webhook and
+// Slack channels use URL (or URLSecretName, for URLs that embed a token);
+// email channels use the SMTP fields. Secret values are names of entries in
+// the secrets store.
+type ChannelConfig struct {
+	URL           string            `json:"url,omitempty"`
+	URLSecretName string            `json:"url_secret_name,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+
+	SMTPHost           string   `json:"smtp_host,omitempty"`
+	SMTPPort           int      `json:"smtp_port,omitempty"`
+	Username           string   `json:"username,omitempty"`
+	PasswordSecretName string   `json:"password_secret_name,omitempty"`
+	StartTLS           bool     `json:"starttls,omitempty"`
+	From               string   `json:"from,omitempty"`
+	To                 []string `json:"to,omitempty"`
+}
+
+func (c *ChannelConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal ChannelConfig value")
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+func (c ChannelConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// NotificationChannel is a destination for alerts that task alert rules
+// reference by name.
+type NotificationChannel struct {
+	ID        uuid.UUID     `json:"id" db:"id"`
+	Name      string        `json:"name" db:"name"`
+	Type      ChannelType   `json:"type" db:"type"`
+	Config    ChannelConfig `json:"config" db:"config"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// RedactedHeaderValue replaces channel header values in API responses. An
+// update that sends it back keeps the stored value.
+const RedactedHeaderValue = "[REDACTED]"
+
+// Redacted returns a copy of the channel with its header values hidden, as
+// they usually carry credentials.
+func (c NotificationChannel) Redacted() NotificationChannel {
+	if len(c.Config.Headers) > 0 {
+		headers := make(map[string]string, len(c.Config.Headers))
+		for name := range c.Config.Headers {
+			headers[name] = RedactedHeaderValue
+		}
+		c.Config.Headers = headers
+	}
+	return c
+}
+
+type CreateChannelRequest struct {
+	Name   string        `json:"name" binding:"required,max=255"`
+	Type   ChannelType   `json:"type" binding:"required,oneof=webhook slack email"`
+	Config ChannelConfig `json:"config" binding:"required"`
+}
+
+type UpdateChannelRequest struct {
+	Config *ChannelConfig `json:"config,omitempty"`
+}
+
+type AlertEvent string
+
+const (
+	AlertFiring   AlertEvent = "firing"
+	AlertResolved AlertEvent = "resolved"
+	AlertTest     AlertEvent = "test"
+)
+
+// AlertDelivery records one attempt to deliver a notification to a channel.
+type AlertDelivery struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	ChannelName string     `json:"channel" db:"channel_name"`
+	TaskID      *uuid.UUID `json:"task_id,omitempty" db:"task_id"`
+	ResultID    *uuid.UUID `json:"result_id,omitempty" db:"result_id"`
+	RuleType    AlertType  `json:"rule_type,omitempty" db:"rule_type"`
+	Event       AlertEvent `json:"event" db:"event"`
+	Attempt     int        `json:"attempt" db:"attempt"`
+	Success     bool       `json:"success" db:"success"`
+	StatusCode  int        `json:"status_code,omitempty" db:"status_code"`
+	Error       string     `json:"error,omitempty" db:"error"`
+	DurationMs  int64      `json:"duration_ms" db:"duration_ms"`
+	AttemptedAt time.Time  `json:"attempted_at" db:"attempted_at"`
+}
+
+type ListDeliveriesParams struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit" binding:"max=100"`
+}
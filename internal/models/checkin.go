@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type TaskType string
+
+const (
+	// TaskTypeHTTP tasks fire their action on their trigger
+	TaskTypeHTTP TaskType = "http"
+	// TaskTypeCheckin tasks have no action; jobs running elsewhere report
+	// to their ping URL instead
+	TaskTypeCheckin TaskType = "checkin"
+)
+
+type CheckinStatus string
+
+const (
+	// CheckinNew is a monitor that has not been pinged yet
+	CheckinNew CheckinStatus = "new"
+	// CheckinUp is a monitor whose last ping reported success
+	CheckinUp CheckinStatus = "up"
+	// CheckinLate is a monitor whose expected ping has not arrived, still
+	// within the grace period
+	CheckinLate CheckinStatus = "late"
+	// CheckinDown is a monitor that reported failure or missed its grace period
+	CheckinDown CheckinStatus = "down"
+)
+
+// Checkin is the state of a check-in monitor. Jobs report to
+// /api/v1/ping/{token}, with /start and /fail variants.
+type Checkin struct {
+	Token      string        `json:"token"`
+	Status     CheckinStatus `json:"status"`
+	StartedAt  *time.Time    `json:"started_at,omitempty"`
+	LastPingAt *time.Time    `json:"last_ping_at,omitempty"`
+}
+
+type PingKind string
+
+const (
+	PingStart   PingKind = "start"
+	PingSuccess PingKind = "success"
+	PingFail    PingKind = "fail"
+)
+
+// Ping is a request received on a check-in monitor's ping URL. It is stored
+// as the result's request.
+type Ping struct {
+	Kind      PingKind `json:"kind"`
+	Method    string   `json:"method"`
+	ClientIP  string   `json:"client_ip"`
+	UserAgent string   `json:"user_agent,omitempty"`
+	Body      string   `json:"-"`
+	BodySize  int64    `json:"-"`
+	Truncated bool     `json:"-"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/version"
+)
+
+// CheckStatus is the outcome of a single health check.
+type CheckStatus string
+
+const (
+	CheckOK     CheckStatus = "ok"
+	CheckFailed CheckStatus = "failed"
+)
+
+// HealthCheck is the result of one readiness check.
+type HealthCheck struct {
+	Name      string                 `json:"name"`
+	Status    CheckStatus            `json:"status"`
+	LatencyMs float64                `json:"latency_ms"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport is the overall readiness of the server.
+type HealthReport struct {
+	Status  CheckStatus   `json:"status"`
+	Checks  []HealthCheck `json:"checks"`
+	Version version.Info  `json:"version"`
+	Uptime  string        `json:"uptime"`
+	Time    time.Time     `json:"time"`
+}
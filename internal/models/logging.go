@@ -0,0 +1,17 @@
+package models
+
+// LogLevels reports the logging levels in effect.
+type LogLevels struct {
+	Default string `json:"default"`
+	// Packages maps every package that logs to its effective level
+	Packages map[string]string `json:"packages"`
+	// Overrides lists the packages whose level differs from the default
+	Overrides []string `json:"overrides"`
+}
+
+// SetLogLevelRequest changes the default level, or one package's level when
+// Package is set. An empty Level removes a package's override.
+type SetLogLevelRequest struct {
+	Package string `json:"package"`
+	Level   string `json:"level"`
+}
@@ -2,8 +2,11 @@ package models
 
 import (
 	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,16 +15,69 @@ import (
 type TaskResult struct {
 	ID              uuid.UUID       `json:"id" db:"id"`
 	TaskID          uuid.UUID       `json:"task_id" db:"task_id"`
+	TaskVersion     int             `json:"task_version,omitempty" db:"task_version"`
 	RunAt           time.Time       `json:"run_at" db:"run_at"`
 	StatusCode      int             `json:"status_code" db:"status_code"`
 	Success         bool            `json:"success" db:"success"`
 	ResponseHeaders json.RawMessage `json:"response_headers,omitempty" db:"response_headers"`
 	ResponseBody    string          `json:"response_body,omitempty" db:"response_body"`
+	BodySize        int64           `json:"body_size" db:"body_size"`
+	BodySHA256      string          `json:"body_sha256,omitempty" db:"body_sha256"`
+	BodyTruncated   bool            `json:"body_truncated" db:"body_truncated"`
+	BodyBlobKey     string          `json:"body_blob_key,omitempty" db:"body_blob_key"`
 	ErrorMessage    *string         `json:"error_message,omitempty" db:"error_message"`
+	ErrorType       ErrorType       `json:"error_type,omitempty" db:"error_type"`
 	DurationMs      int64           `json:"duration_ms" db:"duration_ms"`
+	Request         json.RawMessage `json:"request,omitempty" db:"request"`
+	Timing          *RequestTiming  `json:"timing,omitempty" db:"timing"`
+	TraceID         string          `json:"trace_id,omitempty" db:"trace_id"`
 	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
 }
 
+// ErrorType classifies why an execution failed before or while sending the request.
+type ErrorType string
+
+const (
+	ErrorTypeTemplate ErrorType = "template_error"
+	ErrorTypeSigning  ErrorType = "signing_error"
+	ErrorTypeAuth     ErrorType = "auth_token_error"
+	ErrorTypeTLS      ErrorType = "tls_config_error"
+	ErrorTypeRequest  ErrorType = "request_error"
+	ErrorTypePolicy   ErrorType = "policy_violation"
+	ErrorTypeCircuit  ErrorType = "circuit_open"
+	ErrorTypeThrottle ErrorType = "throttled"
+	// ErrorTypeCheckin marks a check-in monitor's fail ping
+	ErrorTypeCheckin ErrorType = "checkin_failed"
+)
+
+// RequestTiming breaks an execution's HTTP request down into phases. Phases
+// that did not happen, such as DNS on a reused connection, are zero.
+type RequestTiming struct {
+	DNSLookupMs        float64 `json:"dns_lookup_ms"`
+	TCPConnectMs       float64 `json:"tcp_connect_ms"`
+	TLSHandshakeMs     float64 `json:"tls_handshake_ms"`
+	ServerProcessingMs float64 `json:"server_processing_ms"`
+	TimeToFirstByteMs  float64 `json:"time_to_first_byte_ms"`
+	ContentTransferMs  float64 `json:"content_transfer_ms"`
+	RemoteIP           string  `json:"remote_ip,omitempty"`
+	ConnReused         bool    `json:"conn_reused"`
+}
+
+func (t *RequestTiming) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal RequestTiming value")
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+func (t RequestTiming) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
 type ResponseHeaders map[string][]string
 
 func (r *ResponseHeaders) Scan(value interface{}) error {
@@ -46,4 +102,51 @@ type ListResultsParams struct {
 	Success  *bool      `form:"success"`
 	DateFrom *time.Time `form:"date_from"`
 	DateTo   *time.Time `form:"date_to"`
+	// Cursor is the next_cursor of the previous page. When set, Page is
+	// ignored and the total isn't counted.
+	Cursor string `form:"cursor"`
+	// After is the parsed Cursor
+	After *ResultCursor `form:"-"`
+}
+
+// ResultCursor is the position of a result in the newest-first order of
+// result listings, so a listing can resume after it without skipping rows.
+type ResultCursor struct {
+	RunAt time.Time
+	ID    uuid.UUID
+}
+
+// String encodes the cursor as an opaque token.
+func (c ResultCursor) String() string {
+	raw := strconv.FormatInt(c.RunAt.UnixMicro(), 10) + "." + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseResultCursor decodes a token returned by ResultCursor.String.
+func ParseResultCursor(s string) (ResultCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ResultCursor{}, errors.New("invalid cursor")
+	}
+	micros, id, ok := strings.Cut(string(raw), ".")
+	if !ok {
+		return ResultCursor{}, errors.New("invalid cursor")
+	}
+	runAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return ResultCursor{}, errors.New("invalid cursor")
+	}
+	resultID, err := uuid.Parse(id)
+	if err != nil {
+		return ResultCursor{}, errors.New("invalid cursor")
+	}
+	return ResultCursor{RunAt: time.UnixMicro(runAt).UTC(), ID: resultID}, nil
+}
+
+// ResultPartition is one monthly partition of task_results, covering run_at
+// values in [From, To).
+type ResultPartition struct {
+	Name string    `json:"name"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
 }
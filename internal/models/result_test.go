@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResultCursor(t *testing.T) {
+	cursor := ResultCursor{RunAt: time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC), ID: uuid.New()}
+	parsed, err := ParseResultCursor(cursor.String())
+	if err != nil {
+		t.Fatalf("ParseResultCursor() error = %v", err)
+	}
+	if !parsed.RunAt.Equal(cursor.RunAt) || parsed.ID != cursor.ID {
+		t.Errorf("ParseResultCursor() = %+v, want %+v", parsed, cursor)
+	}
+
+	for _, invalid := range []string{"", "not base64!", "bm8tZG90", "MTIzLm5vdC1hLXV1aWQ"} {
+		if _, err := ParseResultCursor(invalid); err == nil {
+			t.Errorf("ParseResultCursor(%q) succeeded, want an error", invalid)
+		}
+	}
+}
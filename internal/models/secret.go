@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Secret is the metadata of an encrypted value. The value itself is never
+// returned by the API.
+type Secret struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name"`
+	KeyID      string    `json:"key_id" db:"key_id"`
+	Ciphertext []byte    `json:"-" db:"ciphertext"`
+	Nonce      []byte    `json:"-" db:"nonce"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateSecretRequest struct {
+	Name  string `json:"name" binding:"required,max=255"`
+	Value string `json:"value" binding:"required"`
+}
+
+type UpdateSecretRequest struct {
+	Value string `json:"value" binding:"required"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StatsParams struct {
+	From     *time.Time `form:"from"`
+	To       *time.Time `form:"to"`
+	Interval string     `form:"interval"`
+}
+
+// ExecutionStats summarizes the runs of one task, or of all tasks, with
+// run_at in [From, To). DurationMs percentiles and StatusCodes cover stored
+// results only; the other figures count every run.
+type ExecutionStats struct {
+	TaskID       *uuid.UUID          `json:"task_id,omitempty"`
+	From         time.Time           `json:"from"`
+	To           time.Time           `json:"to"`
+	Interval     string              `json:"interval"`
+	RunCount     int64               `json:"run_count"`
+	SuccessCount int64               `json:"success_count"`
+	FailureCount int64               `json:"failure_count"`
+	SuccessRate  float64             `json:"success_rate"`
+	DurationMs   DurationPercentiles `json:"duration_ms"`
+	StatusCodes  []StatusCodeCount   `json:"status_codes"`
+	Buckets      []StatsBucket       `json:"buckets"`
+}
+
+type DurationPercentiles struct {
+	Avg float64 `json:"avg"`
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// StatusCodeCount counts results by HTTP status; 0 means no response was received.
+type StatusCodeCount struct {
+	StatusCode int   `json:"status_code"`
+	Count      int64 `json:"count"`
+}
+
+type StatsBucket struct {
+	Start        time.Time `json:"start"`
+	RunCount     int64     `json:"run_count"`
+	FailureCount int64     `json:"failure_count"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StorageMode string
+
+const (
+	StorageAll          StorageMode = "all"
+	StorageFailuresOnly StorageMode = "failures_only"
+	StorageMetadataOnly StorageMode = "metadata_only"
+	StorageNone         StorageMode = "none"
+)
+
+// StoragePolicy controls how much of each run is kept in task_results.
+// A nil policy stores everything.
+type StoragePolicy struct {
+	Mode         StorageMode `json:"mode,omitempty"`
+	MaxBodyBytes int64       `json:"max_body_bytes,omitempty"`
+	Headers      []string    `json:"headers,omitempty"`
+}
+
+// StoresResult reports whether a run with the given outcome is stored at all.
+func (p *StoragePolicy) StoresResult(success bool) bool {
+	if p == nil {
+		return true
+	}
+	switch p.Mode {
+	case StorageNone:
+		return false
+	case StorageFailuresOnly:
+		return !success
+	}
+	return true
+}
+
+// StoresBody reports whether response headers and body are stored for a run.
+func (p *StoragePolicy) StoresBody(success bool) bool {
+	if p == nil {
+		return true
+	}
+	return p.Mode != StorageMetadataOnly && p.StoresResult(success)
+}
+
+func (p *StoragePolicy) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal StoragePolicy value")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+func (p StoragePolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// RetentionPolicy overrides the global result retention for a task. A nil
+// field inherits the global setting; zero keeps results indefinitely.
+type RetentionPolicy struct {
+	MaxAgeDays *int `json:"max_age_days,omitempty"`
+	MaxRows    *int `json:"max_rows,omitempty"`
+}
+
+func (p *RetentionPolicy) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal RetentionPolicy value")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+func (p RetentionPolicy) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// TaskRunCounters are per-task totals that are updated on every run, whether
+// or not the run's result is stored.
+type TaskRunCounters struct {
+	TaskID          uuid.UUID  `json:"-" db:"task_id"`
+	RunCount        int64      `json:"run_count" db:"run_count"`
+	SuccessCount    int64      `json:"success_count" db:"success_count"`
+	FailureCount    int64      `json:"failure_count" db:"failure_count"`
+	StoredCount     int64      `json:"stored_count" db:"stored_count"`
+	TotalDurationMs int64      `json:"total_duration_ms" db:"total_duration_ms"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty" db:"last_run_at"`
+	LastSuccess     *bool      `json:"last_success,omitempty" db:"last_success"`
+	// ConsecutiveFailures is the number of runs that have failed since the
+	// last success
+	ConsecutiveFailures int64 `json:"consecutive_failures" db:"consecutive_failures"`
+}
@@ -13,6 +13,7 @@ type TaskStatus string
 
 const (
 	StatusScheduled TaskStatus = "scheduled"
+	StatusPaused    TaskStatus = "paused"
 	StatusCancelled TaskStatus = "cancelled"
 	StatusCompleted TaskStatus = "completed"
 )
@@ -24,8 +25,10 @@ const (
 	TriggerCron   TriggerType = "cron"
 )
 
+// Trigger schedules an HTTP task. For a check-in task, an optional cron
+// trigger sets when pings are expected.
 type Trigger struct {
-	Type     TriggerType `json:"type" binding:"required,oneof=one-off cron"`
+	Type     TriggerType `json:"type" binding:"omitempty,oneof=one-off cron"`
 	DateTime *time.Time  `json:"datetime,omitempty"`
 	Cron     *string     `json:"cron,omitempty"`
 }
@@ -45,11 +48,33 @@ func (t Trigger) Value() (driver.Value, error) {
 	return json.Marshal(t)
 }
 
+// Action describes the HTTP request fired by a task. URL, Headers and Payload
+// may contain Go templates that are rendered at fire time. Check-in tasks
+// have no action.
 type Action struct {
-	Method  string            `json:"method" binding:"required"`
-	URL     string            `json:"url" binding:"required,url"`
-	Headers map[string]string `json:"headers,omitempty"`
-	Payload json.RawMessage   `json:"payload,omitempty"`
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Payload     json.RawMessage   `json:"payload,omitempty"`
+	Variables   map[string]string `json:"variables,omitempty"`
+	Signing     *SigningConfig    `json:"signing,omitempty"`
+	AuthProfile string            `json:"auth_profile,omitempty"`
+	TLSProfile  string            `json:"tls_profile,omitempty"`
+}
+
+type SigningType string
+
+const (
+	SigningHMAC             SigningType = "hmac"
+	SigningMessageSignature SigningType = "http-message-signature"
+)
+
+// SigningConfig signs outgoing requests with a key held in the secrets store.
+type SigningConfig struct {
+	Type      SigningType `json:"type"`
+	Secret    string      `json:"secret"`
+	KeyID     string      `json:"key_id,omitempty"`
+	Algorithm string      `json:"algorithm,omitempty"`
 }
 
 func (a *Action) Scan(value interface{}) error {
@@ -68,31 +93,62 @@ func (a Action) Value() (driver.Value, error) {
 }
 
 type Task struct {
-	ID        uuid.UUID  `json:"id" db:"id"`
-	Name      string     `json:"name" binding:"required" db:"name"`
-	Trigger   Trigger    `json:"trigger" binding:"required" db:"trigger"`
-	Action    Action     `json:"action" binding:"required" db:"action"`
-	Status    TaskStatus `json:"status" db:"status"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
-	NextRun   *time.Time `json:"next_run,omitempty" db:"next_run"`
+	ID        uuid.UUID        `json:"id" db:"id"`
+	Name      string           `json:"name" binding:"required" db:"name"`
+	Type      TaskType         `json:"type" db:"type"`
+	Version   int              `json:"version" db:"version"`
+	Revision  int64            `json:"revision" db:"revision"`
+	Trigger   Trigger          `json:"trigger" db:"trigger"`
+	Action    Action           `json:"action" db:"action"`
+	Status    TaskStatus       `json:"status" db:"status"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	CreatedBy string           `json:"created_by,omitempty" db:"created_by"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+	UpdatedBy string           `json:"updated_by,omitempty" db:"updated_by"`
+	NextRun   *time.Time       `json:"next_run,omitempty" db:"next_run"`
+	Storage   *StoragePolicy   `json:"storage,omitempty" db:"storage_policy"`
+	Retention *RetentionPolicy `json:"retention,omitempty" db:"retention_policy"`
+	Alerts    AlertRules       `json:"alerts,omitempty" db:"alert_rules"`
+	Heartbeat *HeartbeatPolicy `json:"heartbeat,omitempty" db:"heartbeat_policy"`
+	// Overdue is set while a task with a heartbeat policy has missed its
+	// expected run
+	Overdue      bool             `json:"overdue" db:"-"`
+	OverdueSince *time.Time       `json:"overdue_since,omitempty" db:"overdue_since"`
+	Checkin      *Checkin         `json:"checkin,omitempty" db:"-"`
+	Counters     *TaskRunCounters `json:"counters,omitempty" db:"-"`
 }
 
+// CreateTaskRequest creates an HTTP task, which requires a trigger and an
+// action, or a check-in task, which requires a heartbeat policy instead.
 type CreateTaskRequest struct {
-	Name    string  `json:"name" binding:"required"`
-	Trigger Trigger `json:"trigger" binding:"required"`
-	Action  Action  `json:"action" binding:"required"`
+	Name      string           `json:"name" binding:"required"`
+	Type      TaskType         `json:"type" binding:"omitempty,oneof=http checkin"`
+	Trigger   Trigger          `json:"trigger"`
+	Action    Action           `json:"action"`
+	Storage   *StoragePolicy   `json:"storage,omitempty"`
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+	Alerts    AlertRules       `json:"alerts,omitempty"`
+	Heartbeat *HeartbeatPolicy `json:"heartbeat,omitempty"`
 }
 
+// UpdateTaskRequest changes the fields that are set. ClearHeartbeat removes
+// the task's heartbeat policy, which an omitted Heartbeat leaves unchanged.
 type UpdateTaskRequest struct {
-	Name    *string     `json:"name,omitempty"`
-	Trigger *Trigger    `json:"trigger,omitempty"`
-	Action  *Action     `json:"action,omitempty"`
-	Status  *TaskStatus `json:"status,omitempty"`
+	Name           *string          `json:"name,omitempty"`
+	Trigger        *Trigger         `json:"trigger,omitempty"`
+	Action         *Action          `json:"action,omitempty"`
+	Status         *TaskStatus      `json:"status,omitempty" binding:"omitempty,oneof=scheduled paused cancelled completed"`
+	Storage        *StoragePolicy   `json:"storage,omitempty"`
+	Retention      *RetentionPolicy `json:"retention,omitempty"`
+	Alerts         *AlertRules      `json:"alerts,omitempty"`
+	Heartbeat      *HeartbeatPolicy `json:"heartbeat,omitempty"`
+	ClearHeartbeat bool             `json:"clear_heartbeat,omitempty" binding:"excluded_with=Heartbeat"`
 }
 
 type ListTasksParams struct {
-	Page   int        `form:"page"`
-	Limit  int        `form:"limit" binding:"max=100"`
-	Status TaskStatus `form:"status"`
+	Page    int        `form:"page"`
+	Limit   int        `form:"limit" binding:"max=100"`
+	Status  TaskStatus `form:"status"`
+	Type    TaskType   `form:"type"`
+	Overdue *bool      `form:"overdue"`
 }
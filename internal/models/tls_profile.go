@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TLSProfile holds TLS settings for outgoing task requests. The client
+// private key is the name of an entry in the secrets store.
+type TLSProfile struct {
+	ID                 uuid.UUID `json:"id" db:"id"`
+	Name               string    `json:"name" db:"name"`
+	ClientCert         string    `json:"client_cert,omitempty" db:"client_cert"`
+	ClientKeySecret    string    `json:"client_key_secret,omitempty" db:"client_key_secret"`
+	CABundle           string    `json:"ca_bundle,omitempty" db:"ca_bundle"`
+	ServerName         string    `json:"server_name,omitempty" db:"server_name"`
+	MinVersion         string    `json:"min_version,omitempty" db:"min_version"`
+	InsecureSkipVerify bool      `json:"insecure_skip_verify" db:"insecure_skip_verify"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type CreateTLSProfileRequest struct {
+	Name               string `json:"name" binding:"required,max=255"`
+	ClientCert         string `json:"client_cert,omitempty"`
+	ClientKeySecret    string `json:"client_key_secret,omitempty"`
+	CABundle           string `json:"ca_bundle,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	MinVersion         string `json:"min_version,omitempty" binding:"omitempty,oneof=1.0 1.1 1.2 1.3"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+type UpdateTLSProfileRequest struct {
+	ClientCert         *string `json:"client_cert,omitempty"`
+	ClientKeySecret    *string `json:"client_key_secret,omitempty"`
+	CABundle           *string `json:"ca_bundle,omitempty"`
+	ServerName         *string `json:"server_name,omitempty"`
+	MinVersion         *string `json:"min_version,omitempty" binding:"omitempty,oneof=1.0 1.1 1.2 1.3"`
+	InsecureSkipVerify *bool   `json:"insecure_skip_verify,omitempty"`
+}
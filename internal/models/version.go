@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskVersion is an immutable, numbered snapshot of a task's trigger and
+// action. A new version is saved whenever either changes.
+type TaskVersion struct {
+	TaskID    uuid.UUID `json:"task_id" db:"task_id"`
+	Version   int       `json:"version" db:"version"`
+	Trigger   Trigger   `json:"trigger" db:"trigger"`
+	Action    Action    `json:"action" db:"action"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	// RestoredFrom is set when the version was created by rolling back to an
+	// earlier one
+	RestoredFrom *int `json:"restored_from,omitempty" db:"restored_from"`
+	// Changes is the difference from the previous version
+	Changes AuditChanges `json:"changes,omitempty" db:"-"`
+}
+
+type ListVersionsParams struct {
+	Page  int `form:"page"`
+	Limit int `form:"limit" binding:"max=100"`
+}
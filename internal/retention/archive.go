@@ -0,0 +1,316 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+const (
+	manifestName       = "manifest.json"
+	archiveBlobDir     = "blobs"
+	restoreBatchSize   = 500
+	maxArchiveLineSize = 64 << 20
+)
+
+// archive moves results older than the archive age out of Postgres into
+// per-task, per-month NDJSON files under the archive directory.
+func (p *Pruner) archive() {
+	if p.cfg.ArchiveDir == "" || p.cfg.ArchiveAfterDays <= 0 {
+		return
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -p.cfg.ArchiveAfterDays)
+	groups, err := p.repo.ListResultArchiveGroups(cutoff)
+	if err != nil {
+		logger.Error("Failed to list results to archive", "error", err)
+		return
+	}
+
+	for _, group := range groups {
+		if p.stopping() {
+			return
+		}
+		if err := p.archiveGroup(group, cutoff); err != nil {
+			logger.Error("Failed to archive results", "task_id", group.TaskID, "month", group.Month.Format("2006-01"), "error", err)
+		}
+	}
+}
+
+// archiveGroup writes one task's results for one month (up to cutoff) to a new
+// archive file, records it in the month's manifest and then deletes the rows.
+// Offloaded bodies are copied under the month's blobs directory first, as the
+// originals are deleted with the rows.
+func (p *Pruner) archiveGroup(group models.TaskMonth, cutoff time.Time) error {
+	from := group.Month
+	to := from.AddDate(0, 1, 0)
+	if cutoff.Before(to) {
+		to = cutoff
+	}
+
+	dir := filepath.Join(p.cfg.ArchiveDir, group.TaskID.String(), from.Format("2006-01"))
+	createdAt := time.Now().UTC()
+	name := "results-" + createdAt.Format("20060102T150405Z") + ".jsonl.gz"
+
+	archiveBlobs, err := blobstore.NewFSStore(filepath.Join(dir, archiveBlobDir))
+	if err != nil {
+		return err
+	}
+
+	count, checksum, err := writeJSONL(filepath.Join(dir, name), func(write func(models.TaskResult) error) error {
+		return p.repo.StreamTaskResults(group.TaskID, from, to, func(result models.TaskResult) error {
+			if result.BodyBlobKey != "" {
+				if err := copyBlob(p.blobs, archiveBlobs, result.BodyBlobKey); err != nil {
+					if !errors.Is(err, blobstore.ErrNotFound) {
+						return fmt.Errorf("failed to archive body blob %s: %w", result.BodyBlobKey, err)
+					}
+					logger.Warn("Body blob missing, archiving result without it", "blob_key", result.BodyBlobKey)
+					result.BodyBlobKey = ""
+				}
+			}
+			return write(result)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return os.Remove(filepath.Join(dir, name))
+	}
+
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	manifest.TaskID = group.TaskID
+	manifest.Month = from.Format("2006-01")
+	manifest.Files = append(manifest.Files, models.ArchiveFile{
+		Name:      name,
+		SHA256:    checksum,
+		Results:   count,
+		From:      from,
+		To:        to,
+		CreatedAt: createdAt,
+	})
+	if err := writeManifest(dir, manifest); err != nil {
+		return err
+	}
+
+	deleted := p.prune(group.TaskID, func() (int, []string, error) {
+		return p.repo.PruneTaskResultsBetween(group.TaskID, from, to, p.cfg.BatchSize)
+	})
+	logger.Info("Archived results", "task_id", group.TaskID, "count", count, "path", filepath.Join(dir, name), "deleted", deleted)
+	return nil
+}
+
+func readManifest(dir string) (*models.ArchiveManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &models.ArchiveManifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	manifest := &models.ArchiveManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest in %s: %w", dir, err)
+	}
+	return manifest, nil
+}
+
+func writeManifest(dir string, manifest *models.ArchiveManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, "."+manifestName)
+	if err := os.WriteFile(tmp, data, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, manifestName))
+}
+
+// copyBlob copies the blob stored under key from one store to another. A nil
+// source has no blobs, so the copy fails with blobstore.ErrNotFound.
+func copyBlob(from, to *blobstore.FSStore, key string) error {
+	if from == nil {
+		return blobstore.ErrNotFound
+	}
+	r, err := from.Open(key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := to.Create(key)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// RestoreSummary counts the results a restore loaded and those it skipped
+// because their task no longer exists.
+type RestoreSummary struct {
+	Restored     int
+	Skipped      int
+	SkippedTasks []string
+}
+
+// Restore loads archived results back into task_results, and their bodies
+// into blobs when it is set. path may be a single task-month directory or any
+// directory above them, such as the archive root. Every file is checked
+// against its manifest checksum before it is loaded. Results that already
+// exist are skipped, as are all results of tasks that have been deleted.
+func Restore(repo *db.Repository, blobs *blobstore.FSStore, path string) (RestoreSummary, error) {
+	var summary RestoreSummary
+	skipped := map[string]bool{}
+	err := filepath.WalkDir(path, func(dir string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || entry.Name() != manifestName {
+			return nil
+		}
+
+		manifest, err := readManifest(filepath.Dir(dir))
+		if err != nil {
+			return err
+		}
+		if _, err := repo.GetTaskByID(manifest.TaskID); err != nil {
+			if err.Error() != "task not found" {
+				return err
+			}
+			for _, file := range manifest.Files {
+				summary.Skipped += file.Results
+			}
+			if !skipped[manifest.TaskID.String()] {
+				skipped[manifest.TaskID.String()] = true
+				summary.SkippedTasks = append(summary.SkippedTasks, manifest.TaskID.String())
+			}
+			return nil
+		}
+
+		// Months archived without offloaded bodies have no blobs directory
+		var archiveBlobs *blobstore.FSStore
+		blobDir := filepath.Join(filepath.Dir(dir), archiveBlobDir)
+		if _, err := os.Stat(blobDir); err == nil {
+			if archiveBlobs, err = blobstore.NewFSStore(blobDir); err != nil {
+				return err
+			}
+		}
+		for _, file := range manifest.Files {
+			n, err := restoreFile(repo, blobs, archiveBlobs, filepath.Join(filepath.Dir(dir), file.Name), file)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.Name, err)
+			}
+			summary.Restored += n
+		}
+		return nil
+	})
+	return summary, err
+}
+
+func restoreFile(repo *db.Repository, blobs, archiveBlobs *blobstore.FSStore, path string, file models.ArchiveFile) (int, error) {
+	if err := verifyChecksum(path, file.SHA256); err != nil {
+		return 0, err
+	}
+
+	// Make sure the months being restored have partitions again
+	for month := file.From; month.Before(file.To); month = month.AddDate(0, 1, 0) {
+		if err := repo.EnsureResultPartition(month); err != nil {
+			return 0, err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer gz.Close()
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxArchiveLineSize)
+
+	restored := 0
+	batch := make([]models.TaskResult, 0, restoreBatchSize)
+	flush := func() error {
+		n, err := repo.RestoreTaskResults(batch)
+		restored += n
+		batch = batch[:0]
+		return err
+	}
+
+	for scanner.Scan() {
+		var result models.TaskResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return restored, err
+		}
+		// Bring the archived body back, or drop the reference when there is
+		// nowhere to put it or it wasn't archived
+		if result.BodyBlobKey != "" {
+			if blobs == nil {
+				result.BodyBlobKey = ""
+			} else if err := copyBlob(archiveBlobs, blobs, result.BodyBlobKey); err != nil {
+				if !errors.Is(err, blobstore.ErrNotFound) {
+					return restored, fmt.Errorf("failed to restore body blob %s: %w", result.BodyBlobKey, err)
+				}
+				result.BodyBlobKey = ""
+			}
+		}
+		batch = append(batch, result)
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return restored, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return restored, err
+	}
+	if len(batch) > 0 {
+		if err := flush(); err != nil {
+			return restored, err
+		}
+	}
+	return restored, nil
+}
+
+func verifyChecksum(path, expected string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("checksum mismatch: manifest has %s, file has %s", expected, actual)
+	}
+	return nil
+}
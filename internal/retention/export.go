@@ -0,0 +1,57 @@
+package retention
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// writeJSONL writes results produced by each to path as gzip-compressed
+// newline-delimited JSON. The file only appears once fully written. It
+// returns the number of results and the SHA-256 of the compressed file.
+func writeJSONL(path string, each func(func(models.TaskResult) error) error) (int, string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(tmp, hash))
+	enc := json.NewEncoder(gz)
+
+	count := 0
+	err = each(func(result models.TaskResult) error {
+		count++
+		return enc.Encode(result)
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	if err := gz.Close(); err != nil {
+		return 0, "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		return 0, "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, "", err
+	}
+	return count, hex.EncodeToString(hash.Sum(nil)), nil
+}
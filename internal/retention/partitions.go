@@ -0,0 +1,64 @@
+package retention
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// maintainPartitions creates upcoming monthly partitions of task_results and,
+// when partition retention is set, drops partitions that have fully expired.
+func (p *Pruner) maintainPartitions() {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i <= p.cfg.PartitionsAhead; i++ {
+		if err := p.repo.EnsureResultPartition(month.AddDate(0, i, 0)); err != nil {
+			logger.Error("Failed to create result partition", "month", month.AddDate(0, i, 0).Format("2006-01"), "error", err)
+		}
+	}
+
+	if !p.cfg.Enabled || p.cfg.PartitionRetentionMonths <= 0 {
+		return
+	}
+
+	partitions, err := p.repo.ListResultPartitions()
+	if err != nil {
+		logger.Error("Failed to list result partitions", "error", err)
+		return
+	}
+
+	cutoff := month.AddDate(0, -p.cfg.PartitionRetentionMonths, 0)
+	for _, partition := range partitions {
+		if partition.To.After(cutoff) || p.stopping() {
+			continue
+		}
+		p.dropPartition(partition)
+	}
+}
+
+// dropPartition exports a partition, if an export directory is configured,
+// and then drops it. A failed export keeps the partition in place.
+func (p *Pruner) dropPartition(partition models.ResultPartition) {
+	if p.cfg.ExportDir != "" {
+		path := filepath.Join(p.cfg.ExportDir, partition.Name+".jsonl.gz")
+		count, checksum, err := writeJSONL(path, func(write func(models.TaskResult) error) error {
+			return p.repo.StreamResultPartition(partition, write)
+		})
+		if err != nil {
+			logger.Error("Failed to export result partition, keeping it", "partition", partition.Name, "error", err)
+			return
+		}
+		logger.Info("Exported result partition", "partition", partition.Name, "count", count, "path", path, "sha256", checksum)
+	}
+
+	blobKeys, err := p.repo.DropResultPartition(partition)
+	if err != nil {
+		logger.Error("Failed to drop result partition", "partition", partition.Name, "error", err)
+		return
+	}
+	p.deleteBlobs(blobKeys)
+
+	logger.Info("Dropped result partition", "partition", partition.Name)
+}
@@ -0,0 +1,167 @@
+// Package retention maintains the task_results partitions and prunes old
+// results in the background, keeping daily rollups of everything it deletes.
+package retention
+
+import (
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/logging"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+var logger = logging.For("retention")
+
+type Pruner struct {
+	repo   *db.Repository
+	blobs  *blobstore.FSStore
+	cfg    config.RetentionConfig
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func NewPruner(repo *db.Repository, blobs *blobstore.FSStore, cfg config.RetentionConfig) *Pruner {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1000
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	return &Pruner{
+		repo:   repo,
+		blobs:  blobs,
+		cfg:    cfg,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+// Start runs a pass immediately and then every configured interval.
+// Partitions are always maintained and results archived when an archive is
+// configured; results are only pruned when enabled.
+func (p *Pruner) Start() {
+	if !p.cfg.Enabled {
+		logger.Info("Result retention is disabled")
+	}
+
+	go func() {
+		defer close(p.doneCh)
+
+		ticker := time.NewTicker(p.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			p.Run()
+			select {
+			case <-ticker.C:
+			case <-p.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop ends the background loop, waiting for an in-progress batch to finish.
+func (p *Pruner) Stop() {
+	close(p.stopCh)
+	<-p.doneCh
+}
+
+// Run archives old results and maintains result partitions, and then, when
+// enabled, performs one pruning pass over every task with stored results.
+// Archiving has its own settings and runs whether or not pruning is enabled.
+// It runs first so results are exported before anything deletes them.
+func (p *Pruner) Run() {
+	p.archive()
+	p.maintainPartitions()
+	if !p.cfg.Enabled || p.stopping() {
+		return
+	}
+
+	policies, err := p.repo.ListTaskRetentionPolicies()
+	if err != nil {
+		logger.Error("Failed to list tasks for retention", "error", err)
+		return
+	}
+
+	total := 0
+	for taskID, policy := range policies {
+		maxAgeDays, maxRows := p.effective(policy)
+
+		if maxAgeDays > 0 {
+			cutoff := time.Now().AddDate(0, 0, -maxAgeDays)
+			total += p.prune(taskID, func() (int, []string, error) {
+				return p.repo.PruneTaskResultsBefore(taskID, cutoff, p.cfg.BatchSize)
+			})
+		}
+		if maxRows > 0 {
+			total += p.prune(taskID, func() (int, []string, error) {
+				return p.repo.PruneTaskResultsBeyond(taskID, maxRows, p.cfg.BatchSize)
+			})
+		}
+
+		if p.stopping() {
+			break
+		}
+	}
+
+	if total > 0 {
+		logger.Info("Pruned results", "count", total)
+	}
+}
+
+// effective applies a task's overrides to the global limits.
+func (p *Pruner) effective(policy *models.RetentionPolicy) (maxAgeDays, maxRows int) {
+	maxAgeDays, maxRows = p.cfg.MaxAgeDays, p.cfg.MaxRows
+	if policy != nil {
+		if policy.MaxAgeDays != nil {
+			maxAgeDays = *policy.MaxAgeDays
+		}
+		if policy.MaxRows != nil {
+			maxRows = *policy.MaxRows
+		}
+	}
+	return maxAgeDays, maxRows
+}
+
+// prune calls batch until it deletes less than a full batch, removing the
+// body blobs of deleted results as it goes.
+func (p *Pruner) prune(taskID uuid.UUID, batch func() (int, []string, error)) int {
+	total := 0
+	for {
+		deleted, blobKeys, err := batch()
+		if err != nil {
+			logger.Error("Failed to prune results", "task_id", taskID, "error", err)
+			return total
+		}
+		total += deleted
+		p.deleteBlobs(blobKeys)
+
+		if deleted < p.cfg.BatchSize || p.stopping() {
+			return total
+		}
+	}
+}
+
+func (p *Pruner) deleteBlobs(keys []string) {
+	if p.blobs == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := p.blobs.Delete(key); err != nil {
+			logger.Warn("Failed to delete body blob", "blob_key", key, "error", err)
+		}
+	}
+}
+
+func (p *Pruner) stopping() bool {
+	select {
+	case <-p.stopCh:
+		return true
+	default:
+		return false
+	}
+}
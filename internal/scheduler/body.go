@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
+)
+
+// bodyCapture consumes a response body while keeping only a bounded prefix in
+// memory. It hashes the full body and, when a blob store is configured,
+// spills bodies larger than the threshold to a compressed blob, with secret
+// values redacted.
+type bodyCapture struct {
+	ctx       context.Context
+	maxBytes  int64
+	threshold int64
+	blobMax   int64
+	blobs     *blobstore.FSStore
+	blobKey   string
+	secrets   []string
+
+	head          bytes.Buffer
+	pending       bytes.Buffer
+	hash          hash.Hash
+	size          int64
+	file          io.WriteCloser
+	blob          *redactingWriter
+	blobErr       bool
+	blobTruncated bool
+}
+
+func newBodyCapture(ctx context.Context, maxBytes, threshold, blobMax int64, blobs *blobstore.FSStore, blobKey string, secrets []string) *bodyCapture {
+	return &bodyCapture{
+		ctx:       ctx,
+		maxBytes:  maxBytes,
+		threshold: threshold,
+		blobMax:   blobMax,
+		blobs:     blobs,
+		blobKey:   blobKey,
+		secrets:   secrets,
+		hash:      sha256.New(),
+	}
+}
+
+func (c *bodyCapture) Write(p []byte) (int, error) {
+	c.hash.Write(p)
+
+	if remaining := c.maxBytes - int64(c.head.Len()); remaining > 0 {
+		c.head.Write(p[:min(int64(len(p)), remaining)])
+	}
+
+	if c.blobs != nil && !c.blobErr {
+		c.writeBlob(p)
+	}
+
+	c.size += int64(len(p))
+	return len(p), nil
+}
+
+// writeBlob buffers data until the threshold is crossed, then streams the
+// buffered and subsequent data into a blob, up to blobMax bytes.
+func (c *bodyCapture) writeBlob(p []byte) {
+	if c.blobMax > 0 && c.size >= c.blobMax {
+		c.blobTruncated = true
+		return
+	}
+	if c.blobMax > 0 && c.size+int64(len(p)) > c.blobMax {
+		p = p[:c.blobMax-c.size]
+		c.blobTruncated = true
+	}
+
+	if c.blob == nil {
+		if c.size+int64(len(p)) <= c.threshold {
+			c.pending.Write(p)
+			return
+		}
+		file, err := c.blobs.Create(c.blobKey)
+		if err != nil {
+			logger.ErrorContext(c.ctx, "Failed to create body blob", "blob_key", c.blobKey, "error", err)
+			c.blobErr = true
+			return
+		}
+		c.file = file
+		c.blob = newRedactingWriter(file, c.secrets)
+		if _, err := c.blob.Write(c.pending.Bytes()); err != nil {
+			c.abortBlob(err)
+			return
+		}
+		c.pending = bytes.Buffer{}
+	}
+
+	if _, err := c.blob.Write(p); err != nil {
+		c.abortBlob(err)
+	}
+}
+
+func (c *bodyCapture) abortBlob(err error) {
+	logger.ErrorContext(c.ctx, "Failed to write body blob", "blob_key", c.blobKey, "error", err)
+	c.Discard()
+}
+
+// Discard drops the blob, if one was started, so that a body that could not
+// be read in full is not kept.
+func (c *bodyCapture) Discard() {
+	c.pending = bytes.Buffer{}
+	c.blobErr = true
+	if c.blob == nil {
+		return
+	}
+	c.file.Close()
+	c.blobs.Delete(c.blobKey)
+	c.file = nil
+	c.blob = nil
+}
+
+// Finish closes the blob, if one was written, and returns its key.
+func (c *bodyCapture) Finish() string {
+	c.pending = bytes.Buffer{}
+	if c.blob == nil {
+		return ""
+	}
+	if err := c.blob.Flush(c.blobTruncated); err != nil {
+		c.abortBlob(err)
+		return ""
+	}
+	if err := c.file.Close(); err != nil {
+		logger.ErrorContext(c.ctx, "Failed to store body blob", "blob_key", c.blobKey, "error", err)
+		c.blobs.Delete(c.blobKey)
+		return ""
+	}
+	return c.blobKey
+}
+
+func (c *bodyCapture) Size() int64 {
+	return c.size
+}
+
+// Truncated reports whether the body was longer than a positive maxBytes, or
+// the blob was cut at blobMax. A body discarded with a maxBytes of 0 isn't
+// truncated.
+func (c *bodyCapture) Truncated() bool {
+	return (c.maxBytes > 0 && c.size > c.maxBytes) || c.blobTruncated
+}
+
+func (c *bodyCapture) SHA256() string {
+	return hex.EncodeToString(c.hash.Sum(nil))
+}
+
+// Text returns the captured prefix as a string Postgres can store in a TEXT
+// column: a rune cut off by truncation is dropped, and NUL bytes and invalid
+// UTF-8 are removed.
+func (c *bodyCapture) Text() string {
+	b := c.head.Bytes()
+	if c.Truncated() {
+		for i := 0; i < utf8.UTFMax && len(b) > 0 && !utf8.Valid(b); i++ {
+			b = b[:len(b)-1]
+		}
+	}
+	return strings.ReplaceAll(strings.ToValidUTF8(string(b), ""), "\x00", "")
+}
+
+// redactingWriter replaces secret values in a stream with a placeholder. It
+// holds back the last bytes of each write, up to the longest secret less
+// one, so that a secret split across writes is still caught.
+type redactingWriter struct {
+	w        io.Writer
+	secrets  [][]byte
+	holdBack int
+	buf      []byte
+}
+
+func newRedactingWriter(w io.Writer, secrets []string) *redactingWriter {
+	r := &redactingWriter{w: w}
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		r.secrets = append(r.secrets, []byte(secret))
+		r.holdBack = max(r.holdBack, len(secret)-1)
+	}
+	// Longest first, so a secret that contains another is replaced whole
+	sort.SliceStable(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	return r
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	if len(r.secrets) == 0 {
+		return r.w.Write(p)
+	}
+
+	r.buf = append(r.buf, p...)
+	for _, secret := range r.secrets {
+		r.buf = bytes.ReplaceAll(r.buf, secret, []byte(redactedValue))
+	}
+
+	if flush := len(r.buf) - r.holdBack; flush > 0 {
+		if _, err := r.w.Write(r.buf[:flush]); err != nil {
+			return 0, err
+		}
+		r.buf = append(r.buf[:0], r.buf[flush:]...)
+	}
+	return len(p), nil
+}
+
+// Flush writes out the held-back tail. When the stream was cut short, a tail
+// that could be the start of a secret is redacted too.
+func (r *redactingWriter) Flush(cut bool) error {
+	if len(r.buf) == 0 {
+		return nil
+	}
+	if cut {
+		r.buf = redactPartialSecret(r.buf, r.secrets)
+	}
+	_, err := r.w.Write(r.buf)
+	r.buf = nil
+	return err
+}
+
+// redactPartialSecret replaces the longest suffix of b that is a prefix of a
+// secret.
+func redactPartialSecret(b []byte, secrets [][]byte) []byte {
+	for i := range b {
+		for _, secret := range secrets {
+			if len(b)-i < len(secret) && bytes.HasPrefix(secret, b[i:]) {
+				return append(b[:i], redactedValue...)
+			}
+		}
+	}
+	return b
+}
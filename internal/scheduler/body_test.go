@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
+)
+
+// capture writes body to a new bodyCapture in chunks of chunkSize bytes.
+func capture(t *testing.T, body string, chunkSize int, maxBytes, threshold, blobMax int64, blobs *blobstore.FSStore, secrets ...string) *bodyCapture {
+	t.Helper()
+	c := newBodyCapture(context.Background(), maxBytes, threshold, blobMax, blobs, "results/test", secrets)
+	for len(body) > 0 {
+		n := min(chunkSize, len(body))
+		if _, err := c.Write([]byte(body[:n])); err != nil {
+			t.Fatal(err)
+		}
+		body = body[n:]
+	}
+	return c
+}
+
+func TestBodyCapture(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		maxBytes      int64
+		wantText      string
+		wantTruncated bool
+	}{
+		{name: "under the cap", body: `{"ok":true}`, maxBytes: 64, wantText: `{"ok":true}`},
+		{name: "at the cap", body: "abcd", maxBytes: 4, wantText: "abcd"},
+		{name: "over the cap", body: "abcdefgh", maxBytes: 4, wantText: "abcd", wantTruncated: true},
+		{name: "discarded on purpose", body: "abcdefgh", maxBytes: 0, wantText: ""},
+		{name: "cut inside a rune", body: "ab€cd", maxBytes: 3, wantText: "ab", wantTruncated: true},
+		{name: "invalid UTF-8 and NUL bytes", body: "a\xffb\x00c", maxBytes: 64, wantText: "abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := capture(t, tt.body, 3, tt.maxBytes, 0, 0, nil)
+			if key := c.Finish(); key != "" {
+				t.Errorf("Finish() = %q, want no blob without a store", key)
+			}
+			if got := c.Text(); got != tt.wantText {
+				t.Errorf("Text() = %q, want %q", got, tt.wantText)
+			}
+			if got := c.Truncated(); got != tt.wantTruncated {
+				t.Errorf("Truncated() = %v, want %v", got, tt.wantTruncated)
+			}
+			if got := c.Size(); got != int64(len(tt.body)) {
+				t.Errorf("Size() = %d, want %d", got, len(tt.body))
+			}
+			sum := sha256.Sum256([]byte(tt.body))
+			if got := c.SHA256(); got != hex.EncodeToString(sum[:]) {
+				t.Errorf("SHA256() = %s, want the hash of the full body", got)
+			}
+		})
+	}
+}
+
+func TestBodyCaptureBlob(t *testing.T) {
+	const secret = "s3cr3t-value"
+
+	tests := []struct {
+		name          string
+		body          string
+		threshold     int64
+		blobMax       int64
+		wantBlob      string // empty means no blob is kept
+		wantTruncated bool
+	}{
+		{name: "under the threshold", body: "short", threshold: 64},
+		{name: "over the threshold", body: strings.Repeat("x", 40), threshold: 16, wantBlob: strings.Repeat("x", 40)},
+		{
+			name:      "secret split across writes",
+			body:      "token=" + secret + "&more=" + strings.Repeat("y", 20),
+			threshold: 8,
+			wantBlob:  "token=" + redactedValue + "&more=" + strings.Repeat("y", 20),
+		},
+		{
+			name:          "cut at the blob cap",
+			body:          strings.Repeat("z", 30) + "tail",
+			threshold:     8,
+			blobMax:       30,
+			wantBlob:      strings.Repeat("z", 30),
+			wantTruncated: true,
+		},
+		{
+			name:          "cut inside a secret",
+			body:          strings.Repeat("z", 20) + secret,
+			threshold:     8,
+			blobMax:       24,
+			wantBlob:      strings.Repeat("z", 20) + redactedValue,
+			wantTruncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blobs, err := blobstore.NewFSStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := capture(t, tt.body, 5, 1024, tt.threshold, tt.blobMax, blobs, secret)
+			key := c.Finish()
+			if got := c.Truncated(); got != tt.wantTruncated {
+				t.Errorf("Truncated() = %v, want %v", got, tt.wantTruncated)
+			}
+
+			if tt.wantBlob == "" {
+				if key != "" {
+					t.Errorf("Finish() = %q, want no blob", key)
+				}
+				return
+			}
+			if key == "" {
+				t.Fatal("Finish() kept no blob")
+			}
+			r, err := blobs.Open(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.wantBlob {
+				t.Errorf("blob = %q, want %q", got, tt.wantBlob)
+			}
+		})
+	}
+}
+
+func TestBodyCaptureDiscard(t *testing.T) {
+	blobs, err := blobstore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := capture(t, strings.Repeat("x", 40), 5, 1024, 8, 0, blobs)
+	c.Discard()
+	if key := c.Finish(); key != "" {
+		t.Fatalf("Finish() after Discard() = %q, want no blob", key)
+	}
+	if _, err := blobs.Open("results/test"); err != blobstore.ErrNotFound {
+		t.Errorf("Open() of a discarded blob error = %v, want %v", err, blobstore.ErrNotFound)
+	}
+}
+
+func TestRedactingWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := newRedactingWriter(&out, []string{"secret", "", "another-secret"})
+	for _, chunk := range []string{"a sec", "ret and an", "other-sec", "ret end"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(false); err != nil {
+		t.Fatal(err)
+	}
+	want := "a " + redactedValue + " and " + redactedValue + " end"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
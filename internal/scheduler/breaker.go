@@ -0,0 +1,165 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+type hostBreaker struct {
+	state               models.CircuitState
+	consecutiveFailures int
+	lastError           string
+	lastFailureAt       time.Time
+	openedAt            time.Time
+	probeInFlight       bool
+}
+
+// breakerRegistry tracks a circuit breaker per target host. After threshold
+// consecutive failures a breaker opens and rejects runs until the cool-down
+// has passed, then lets a single probe through in the half-open state.
+type breakerRegistry struct {
+	enabled   bool
+	threshold int
+	cooldown  time.Duration
+	mu        sync.Mutex
+	breakers  map[string]*hostBreaker
+}
+
+func newBreakerRegistry(cfg config.CircuitBreakerConfig) *breakerRegistry {
+	threshold := cfg.FailureThreshold
+	if threshold <= 0 {
+		threshold = 5
+	}
+	return &breakerRegistry{
+		enabled:   cfg.Enabled,
+		threshold: threshold,
+		cooldown:  cfg.Cooldown,
+		breakers:  make(map[string]*hostBreaker),
+	}
+}
+
+// Allow reports whether a request to host may be attempted.
+func (r *breakerRegistry) Allow(host string) bool {
+	if !r.enabled {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[host]
+	if !ok {
+		return true
+	}
+
+	switch b.state {
+	case models.CircuitOpen:
+		if time.Since(b.openedAt) < r.cooldown {
+			return false
+		}
+		b.state = models.CircuitHalfOpen
+		b.probeInFlight = true
+		return true
+	case models.CircuitHalfOpen:
+		if b.probeInFlight {
+			return false
+		}
+		b.probeInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record updates the breaker for host with the outcome of a request.
+func (r *breakerRegistry) Record(host string, failed bool, errMsg string) {
+	if !r.enabled {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[host]
+	if !ok {
+		if !failed {
+			return
+		}
+		b = &hostBreaker{state: models.CircuitClosed}
+		r.breakers[host] = b
+	}
+
+	b.probeInFlight = false
+
+	if !failed {
+		delete(r.breakers, host)
+		return
+	}
+
+	now := time.Now()
+	b.consecutiveFailures++
+	b.lastError = errMsg
+	b.lastFailureAt = now
+
+	if b.state == models.CircuitHalfOpen || b.consecutiveFailures >= r.threshold {
+		b.state = models.CircuitOpen
+		b.openedAt = now
+	}
+}
+
+// Release frees a half-open probe slot without recording an outcome, for
+// runs that failed before reaching the target.
+func (r *breakerRegistry) Release(host string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if b, ok := r.breakers[host]; ok {
+		b.probeInFlight = false
+	}
+}
+
+// Reset closes the breaker for host. It reports whether a breaker existed.
+func (r *breakerRegistry) Reset(host string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.breakers[host]; !ok {
+		return false
+	}
+	delete(r.breakers, host)
+	return true
+}
+
+// States returns a snapshot of every host with recorded failures.
+func (r *breakerRegistry) States() []models.CircuitBreakerState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states := make([]models.CircuitBreakerState, 0, len(r.breakers))
+	for host, b := range r.breakers {
+		lastFailureAt := b.lastFailureAt
+		state := models.CircuitBreakerState{
+			Host:                host,
+			State:               b.state,
+			ConsecutiveFailures: b.consecutiveFailures,
+			LastError:           b.lastError,
+			LastFailureAt:       &lastFailureAt,
+		}
+		if b.state != models.CircuitClosed {
+			openedAt := b.openedAt
+			retryAt := b.openedAt.Add(r.cooldown)
+			state.OpenedAt = &openedAt
+			state.RetryAt = &retryAt
+		}
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Host < states[j].Host
+	})
+	return states
+}
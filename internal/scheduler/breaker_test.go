@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// expireCooldown moves the host's breaker back past its cool-down.
+func expireCooldown(r *breakerRegistry, host string) {
+	r.breakers[host].openedAt = time.Now().Add(-2 * r.cooldown)
+}
+
+// stateOf returns the host's breaker state, or closed if it has none.
+func stateOf(r *breakerRegistry, host string) models.CircuitState {
+	for _, state := range r.States() {
+		if state.Host == host {
+			return state.State
+		}
+	}
+	return models.CircuitClosed
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	const host = "api.example.com"
+	r := newBreakerRegistry(config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 2, Cooldown: time.Minute})
+
+	// closed: failures below the threshold still let runs through
+	r.Record(host, true, "HTTP 503")
+	if !r.Allow(host) || stateOf(r, host) != models.CircuitClosed {
+		t.Fatalf("after 1 failure: state %s, want closed", stateOf(r, host))
+	}
+
+	// open: the threshold is reached and runs are rejected during the cool-down
+	r.Record(host, true, "HTTP 503")
+	if stateOf(r, host) != models.CircuitOpen {
+		t.Fatalf("after 2 failures: state %s, want open", stateOf(r, host))
+	}
+	if r.Allow(host) {
+		t.Fatal("Allow() during the cool-down = true, want false")
+	}
+
+	// half-open: one probe is let through after the cool-down
+	expireCooldown(r, host)
+	if !r.Allow(host) {
+		t.Fatal("Allow() after the cool-down = false, want a probe")
+	}
+	if stateOf(r, host) != models.CircuitHalfOpen {
+		t.Fatalf("after the cool-down: state %s, want half-open", stateOf(r, host))
+	}
+	if r.Allow(host) {
+		t.Fatal("Allow() with a probe in flight = true, want false")
+	}
+
+	// a failed probe opens the breaker again
+	r.Record(host, true, "HTTP 503")
+	if stateOf(r, host) != models.CircuitOpen || r.Allow(host) {
+		t.Fatalf("after a failed probe: state %s, want open", stateOf(r, host))
+	}
+
+	// a released probe frees the slot for another
+	expireCooldown(r, host)
+	if !r.Allow(host) {
+		t.Fatal("Allow() after the cool-down = false, want a probe")
+	}
+	r.Release(host)
+	if !r.Allow(host) {
+		t.Fatal("Allow() after releasing the probe = false, want another probe")
+	}
+
+	// closed: a successful probe clears the breaker
+	r.Record(host, false, "")
+	if stateOf(r, host) != models.CircuitClosed || !r.Allow(host) {
+		t.Fatalf("after a successful probe: state %s, want closed", stateOf(r, host))
+	}
+	if len(r.States()) != 0 {
+		t.Errorf("States() = %v, want no breakers after recovery", r.States())
+	}
+}
+
+func TestBreakerReset(t *testing.T) {
+	const host = "api.example.com"
+	r := newBreakerRegistry(config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 1, Cooldown: time.Minute})
+
+	if r.Reset(host) {
+		t.Error("Reset() of a host without a breaker = true, want false")
+	}
+
+	r.Record(host, true, "connection refused")
+	if r.Allow(host) {
+		t.Fatal("Allow() of an open breaker = true, want false")
+	}
+	if !r.Reset(host) {
+		t.Error("Reset() of an open breaker = false, want true")
+	}
+	if !r.Allow(host) {
+		t.Error("Allow() after Reset() = false, want true")
+	}
+
+	// A reset breaker counts failures from zero
+	r.Record("other.example.com", true, "timeout")
+	if other := stateOf(r, "other.example.com"); other != models.CircuitOpen {
+		t.Errorf("other host state %s, want open", other)
+	}
+	if stateOf(r, host) != models.CircuitClosed {
+		t.Errorf("reset host state %s, want closed", stateOf(r, host))
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	const host = "api.example.com"
+	r := newBreakerRegistry(config.CircuitBreakerConfig{FailureThreshold: 1, Cooldown: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		r.Record(host, true, "HTTP 500")
+	}
+	if !r.Allow(host) {
+		t.Error("Allow() with breakers disabled = false, want true")
+	}
+	if len(r.States()) != 0 {
+		t.Errorf("States() with breakers disabled = %v, want none", r.States())
+	}
+}
@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+)
+
+// ErrEgressBlocked is returned when a request targets a destination that the
+// egress policy does not allow.
+var ErrEgressBlocked = errors.New("egress policy violation")
+
+// defaultBlockedRanges covers loopback, private, link-local, carrier-grade NAT,
+// multicast and other non-public address space. The NAT64 and 6to4 prefixes
+// are blocked whole, since they embed IPv4 addresses that may be private.
+var defaultBlockedRanges = mustParsePrefixes(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"2002::/16",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+// EgressPolicy decides which destinations task requests may connect to. It is
+// enforced when dialing, after DNS resolution, so a hostname that later
+// resolves to a blocked address (DNS rebinding) is still caught.
+type EgressPolicy struct {
+	blockPrivate bool
+	allowCIDRs   []netip.Prefix
+	denyCIDRs    []netip.Prefix
+	allowHosts   []string
+	denyHosts    []string
+}
+
+func NewEgressPolicy(cfg config.EgressConfig) (*EgressPolicy, error) {
+	allowCIDRs, err := parsePrefixes(cfg.AllowCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid egress allow CIDR: %w", err)
+	}
+	denyCIDRs, err := parsePrefixes(cfg.DenyCIDRs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid egress deny CIDR: %w", err)
+	}
+
+	return &EgressPolicy{
+		blockPrivate: cfg.BlockPrivate,
+		allowCIDRs:   allowCIDRs,
+		denyCIDRs:    denyCIDRs,
+		allowHosts:   normalizeHosts(cfg.AllowHosts),
+		denyHosts:    normalizeHosts(cfg.DenyHosts),
+	}, nil
+}
+
+// CheckHost validates a URL host before any request is made. Literal IP
+// addresses are checked against the CIDR rules; hostnames only against the
+// hostname lists, since their addresses are checked at dial time.
+func (p *EgressPolicy) CheckHost(host string) error {
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if matchHost(p.denyHosts, host) {
+		return fmt.Errorf("%w: host %s is denied", ErrEgressBlocked, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.checkAddr(addr, matchHost(p.allowHosts, host))
+	}
+	return nil
+}
+
+// DialContext dials addr and rejects the connection if the resolved address
+// is not allowed.
+func (p *EgressPolicy) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	host = strings.ToLower(host)
+
+	if matchHost(p.denyHosts, host) {
+		return nil, fmt.Errorf("%w: host %s is denied", ErrEgressBlocked, host)
+	}
+	hostAllowed := matchHost(p.allowHosts, host)
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			ipPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: cannot parse dial address %s", ErrEgressBlocked, address)
+			}
+			return p.checkAddr(ipPort.Addr(), hostAllowed)
+		},
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+func (p *EgressPolicy) checkAddr(addr netip.Addr, hostAllowed bool) error {
+	addr = addr.Unmap()
+
+	if containsAddr(p.denyCIDRs, addr) {
+		return fmt.Errorf("%w: address %s is denied", ErrEgressBlocked, addr)
+	}
+	if containsAddr(p.allowCIDRs, addr) || hostAllowed {
+		return nil
+	}
+	if p.blockPrivate && containsAddr(defaultBlockedRanges, addr) {
+		return fmt.Errorf("%w: address %s is in a private or reserved range", ErrEgressBlocked, addr)
+	}
+	return nil
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHost reports whether host matches one of the patterns. A pattern of
+// the form "*.example.com" matches any subdomain of example.com.
+func matchHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "*.") {
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHosts(hosts []string) []string {
+	normalized := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		normalized = append(normalized, strings.ToLower(strings.TrimSpace(host)))
+	}
+	return normalized
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func mustParsePrefixes(values ...string) []netip.Prefix {
+	prefixes, err := parsePrefixes(values)
+	if err != nil {
+		panic(err)
+	}
+	return prefixes
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+)
+
+func TestEgressPolicyCheckHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.EgressConfig
+		host    string
+		blocked bool
+	}{
+		{name: "public IPv4", cfg: config.EgressConfig{BlockPrivate: true}, host: "93.184.216.34"},
+		{name: "public IPv6", cfg: config.EgressConfig{BlockPrivate: true}, host: "2606:2800:220:1::1"},
+		{name: "hostname is checked at dial time", cfg: config.EgressConfig{BlockPrivate: true}, host: "localhost"},
+		{name: "loopback", cfg: config.EgressConfig{BlockPrivate: true}, host: "127.0.0.1", blocked: true},
+		{name: "private 10/8", cfg: config.EgressConfig{BlockPrivate: true}, host: "10.1.2.3", blocked: true},
+		{name: "private 172.16/12", cfg: config.EgressConfig{BlockPrivate: true}, host: "172.31.255.1", blocked: true},
+		{name: "private 192.168/16", cfg: config.EgressConfig{BlockPrivate: true}, host: "192.168.0.1", blocked: true},
+		{name: "carrier-grade NAT", cfg: config.EgressConfig{BlockPrivate: true}, host: "100.64.0.1", blocked: true},
+		{name: "link-local metadata", cfg: config.EgressConfig{BlockPrivate: true}, host: "169.254.169.254", blocked: true},
+		{name: "unspecified", cfg: config.EgressConfig{BlockPrivate: true}, host: "0.0.0.0", blocked: true},
+		{name: "multicast", cfg: config.EgressConfig{BlockPrivate: true}, host: "224.0.0.1", blocked: true},
+		{name: "IPv6 loopback", cfg: config.EgressConfig{BlockPrivate: true}, host: "[::1]", blocked: true},
+		{name: "IPv6 unique local", cfg: config.EgressConfig{BlockPrivate: true}, host: "fd00::1", blocked: true},
+		{name: "IPv6 link-local", cfg: config.EgressConfig{BlockPrivate: true}, host: "fe80::1", blocked: true},
+		{name: "IPv4-mapped loopback", cfg: config.EgressConfig{BlockPrivate: true}, host: "::ffff:127.0.0.1", blocked: true},
+		{name: "NAT64", cfg: config.EgressConfig{BlockPrivate: true}, host: "64:ff9b::a00:1", blocked: true},
+		{name: "6to4", cfg: config.EgressConfig{BlockPrivate: true}, host: "2002:a00:1::1", blocked: true},
+		{name: "private allowed when blocking is off", cfg: config.EgressConfig{}, host: "10.1.2.3"},
+		{name: "allowed CIDR", cfg: config.EgressConfig{BlockPrivate: true, AllowCIDRs: []string{"10.20.0.0/16"}}, host: "10.20.1.1"},
+		{name: "outside allowed CIDR", cfg: config.EgressConfig{BlockPrivate: true, AllowCIDRs: []string{"10.20.0.0/16"}}, host: "10.21.1.1", blocked: true},
+		{name: "denied CIDR", cfg: config.EgressConfig{DenyCIDRs: []string{"93.184.216.0/24"}}, host: "93.184.216.34", blocked: true},
+		{name: "deny beats allow", cfg: config.EgressConfig{AllowCIDRs: []string{"10.0.0.0/8"}, DenyCIDRs: []string{"10.0.0.1/32"}}, host: "10.0.0.1", blocked: true},
+		{name: "denied host", cfg: config.EgressConfig{DenyHosts: []string{"internal.example.com"}}, host: "Internal.Example.com", blocked: true},
+		{name: "denied subdomain", cfg: config.EgressConfig{DenyHosts: []string{"*.example.com"}}, host: "api.example.com", blocked: true},
+		{name: "wildcard does not match apex", cfg: config.EgressConfig{DenyHosts: []string{"*.example.com"}}, host: "example.com"},
+		{name: "allowed host", cfg: config.EgressConfig{BlockPrivate: true, AllowHosts: []string{"10.1.2.3"}}, host: "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewEgressPolicy(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = policy.CheckHost(tt.host)
+			if blocked := errors.Is(err, ErrEgressBlocked); blocked != tt.blocked {
+				t.Errorf("CheckHost(%q) error = %v, blocked %v", tt.host, err, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestNewEgressPolicyRejectsInvalidCIDR(t *testing.T) {
+	if _, err := NewEgressPolicy(config.EgressConfig{AllowCIDRs: []string{"10.0.0.0/33"}}); err == nil {
+		t.Error("NewEgressPolicy() accepted an invalid allow CIDR")
+	}
+	if _, err := NewEgressPolicy(config.EgressConfig{DenyCIDRs: []string{"not-an-ip"}}); err == nil {
+		t.Error("NewEgressPolicy() accepted an invalid deny CIDR")
+	}
+}
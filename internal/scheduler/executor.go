@@ -1,119 +1,425 @@
 package scheduler
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
+	"github.com/ayushsarode/task-scheduler/internal/config"
 	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/logging"
+	"github.com/ayushsarode/task-scheduler/internal/metrics"
 	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
+const requestTimeout = 30 * time.Second
+
+// ResultObserver is notified of every run once its result has been saved,
+// with the task's run counters as saving it left them. Observe is called on
+// its own goroutine, so runs may be observed out of order.
+type ResultObserver interface {
+	Observe(ctx context.Context, task *models.Task, result *models.TaskResult, counters *models.TaskRunCounters)
+}
+
 type Executor struct {
-	repo   *db.Repository
-	client *http.Client
+	repo       *db.Repository
+	secrets    SecretResolver
+	egress     *EgressPolicy
+	breakers   *breakerRegistry
+	limiter    *rateLimiter
+	client     *http.Client
+	tlsClients *clientPool
+	tokens     *tokenCache
+	blobs      *blobstore.FSStore
+	body       config.BodyConfig
+	observer   ResultObserver
 }
 
-func NewExecutor(repo *db.Repository) *Executor {
-	return &Executor{
-		repo: repo,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+func NewExecutor(repo *db.Repository, secrets SecretResolver, blobs *blobstore.FSStore, observer ResultObserver, cfg config.ExecutorConfig) (*Executor, error) {
+	egress, err := NewEgressPolicy(cfg.Egress)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Executor{
+		repo:     repo,
+		secrets:  secrets,
+		egress:   egress,
+		breakers: newBreakerRegistry(cfg.CircuitBreaker),
+		limiter:  newRateLimiter(cfg.RateLimit),
+		blobs:    blobs,
+		body:     cfg.Body,
+		observer: observer,
+	}
+	e.client = &http.Client{
+		Timeout:   requestTimeout,
+		Transport: e.newTransport(),
 	}
+	e.tlsClients = newClientPool(requestTimeout, secrets, cfg.AllowInsecureTLS, e.newTransport)
+	e.tokens = newTokenCache(secrets)
+	return e, nil
 }
 
-func (e *Executor) ExecuteTask(task *models.Task) {
-	log.Printf("Executing task: %s (ID: %s)", task.Name, task.ID)
+// newTransport returns the base transport used for all outgoing requests.
+// Connections go through the egress policy, and environment proxies are
+// ignored so the policy sees the real destination.
+func (e *Executor) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = e.egress.DialContext
+	return transport
+}
 
+func (e *Executor) ExecuteTask(task *models.Task, scheduledAt time.Time) {
 	startTime := time.Now()
+	defer metrics.ExecutionStarted(string(task.Trigger.Type), scheduledAt, startTime)()
+
 	result := &models.TaskResult{
-		ID:        uuid.New(),
-		TaskID:    task.ID,
-		RunAt:     startTime,
-		CreatedAt: time.Now(),
+		ID:          uuid.New(),
+		TaskID:      task.ID,
+		TaskVersion: task.Version,
+		RunAt:       startTime,
+		CreatedAt:   time.Now(),
 	}
 
-	// Prepare HTTP request
-	var reqBody io.Reader
-	if task.Action.Payload != nil && len(task.Action.Payload) > 0 {
-		reqBody = bytes.NewReader(task.Action.Payload)
-	}
+	// Each execution is the root of its own trace
+	ctx, span := tracing.Start(context.Background(), "task.execute",
+		attribute.String("task.id", task.ID.String()),
+		attribute.String("task.name", task.Name),
+		attribute.String("task.run_id", result.ID.String()),
+		attribute.String("task.trigger", string(task.Trigger.Type)),
+		attribute.String("task.scheduled_at", scheduledAt.UTC().Format(time.RFC3339Nano)),
+	)
+	defer span.End()
+	result.TraceID = tracing.TraceID(ctx)
 
-	req, err := http.NewRequest(task.Action.Method, task.Action.URL, reqBody)
+	// Every log line for this run carries the task and run IDs
+	ctx = logging.WithAttrs(ctx, "task_id", task.ID, "run_id", result.ID)
+	logger.InfoContext(ctx, "Executing task", "task_name", task.Name, "trigger", task.Trigger.Type, "scheduled_at", scheduledAt)
+
+	// Render action templates
+	rendered, err := RenderAction(task.Action, TemplateData{
+		TaskID:        task.ID,
+		TaskName:      task.Name,
+		RunID:         result.ID,
+		ScheduledTime: scheduledAt,
+		Now:           startTime,
+		Attempt:       1,
+	}, e.secrets)
 	if err != nil {
-		result.Success = false
-		errorMsg := fmt.Sprintf("Failed to create request: %v", err)
-		result.ErrorMessage = &errorMsg
-		result.DurationMs = time.Since(startTime).Milliseconds()
-		e.saveResult(result)
+		e.fail(ctx, task, result, startTime, models.ErrorTypeTemplate, fmt.Sprintf("Failed to render action: %v", err))
 		return
 	}
 
-	// Set headers
-	if task.Action.Headers != nil {
-		for key, value := range task.Action.Headers {
-			req.Header.Set(key, value)
-		}
+	// Store the rendered request with the result, with secret values redacted
+	if requestJSON, err := json.Marshal(rendered.Redacted()); err != nil {
+		logger.ErrorContext(ctx, "Failed to marshal rendered request", "error", err)
+	} else {
+		result.Request = json.RawMessage(requestJSON)
 	}
 
-	// Set default Content-Type if payload exists
-	if task.Action.Payload != nil && req.Header.Get("Content-Type") == "" {
-		req.Header.Set("Content-Type", "application/json")
+	// Short-circuit while the target host's breaker is open
+	host := requestHost(rendered.URL)
+	if !e.breakers.Allow(host) {
+		e.fail(ctx, task, result, startTime, models.ErrorTypeCircuit, fmt.Sprintf("Circuit breaker open for host %s", host))
+		return
 	}
 
-	// Execute request
-	resp, err := e.client.Do(req)
+	// Wait for the host's rate limit and concurrency cap
+	release, waited, err := e.limiter.Acquire(host)
 	if err != nil {
-		result.Success = false
-		errorMsg := fmt.Sprintf("Request failed: %v", err)
-		result.ErrorMessage = &errorMsg
-		result.DurationMs = time.Since(startTime).Milliseconds()
-		e.saveResult(result)
+		e.breakers.Release(host)
+		e.fail(ctx, task, result, startTime, models.ErrorTypeThrottle, err.Error())
 		return
 	}
-	defer resp.Body.Close()
+	defer func() { release() }()
+	if waited > 0 {
+		logger.InfoContext(ctx, "Waited for rate limit", "host", host, "waited", waited)
+	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Execute request, retrying once with a fresh token if the target rejects it
+	trace := newRequestTrace()
+	resp, errorType, err := e.send(ctx, task, rendered, trace, "")
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && task.Action.AuthProfile != "" {
+		resp.Body.Close()
+		rejected := strings.TrimPrefix(resp.Request.Header.Get("Authorization"), "Bearer ")
+
+		// The retry is another request to the host, so it waits its turn too
+		release()
+		if release, waited, err = e.limiter.Acquire(host); err != nil {
+			release = func() {}
+			e.breakers.Record(host, false, resp.Status)
+			e.fail(ctx, task, result, startTime, models.ErrorTypeThrottle, err.Error())
+			return
+		}
+		if waited > 0 {
+			logger.InfoContext(ctx, "Waited for rate limit", "host", host, "waited", waited)
+		}
+
+		trace = newRequestTrace()
+		resp, errorType, err = e.send(ctx, task, rendered, trace, rejected)
+	}
 	if err != nil {
-		log.Printf("Failed to read response body: %v", err)
-		body = []byte{}
+		errorMsg := rendered.Redact(err.Error())
+		if errorType == models.ErrorTypeRequest {
+			result.Timing = trace.Timing(time.Now())
+			e.breakers.Record(host, true, errorMsg)
+		} else {
+			// The target was never reached, so leave the breaker as it is
+			e.breakers.Release(host)
+		}
+		e.fail(ctx, task, result, startTime, errorType, errorMsg)
+		return
 	}
+	defer resp.Body.Close()
+
+	e.breakers.Record(host, resp.StatusCode >= 500, resp.Status)
 
-	// Calculate duration
-	result.DurationMs = time.Since(startTime).Milliseconds()
 	result.StatusCode = resp.StatusCode
 	result.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
-	result.ResponseBody = string(body)
+
+	// Read response body, keeping a bounded prefix and offloading large bodies.
+	// Bodies the storage policy discards are still read for size and hash.
+	maxBytes, blobs := e.body.MaxBytes, e.blobs
+	if !task.Storage.StoresBody(result.Success) {
+		maxBytes, blobs = 0, nil
+	} else if limit := task.Storage.MaxBodyBytes; limit > 0 && limit < maxBytes {
+		maxBytes = limit
+	}
+	body := newBodyCapture(ctx, maxBytes, e.body.BlobThresholdBytes, e.body.BlobMaxBytes, blobs, bodyBlobKey(result), rendered.SecretValues())
+	if _, err := io.Copy(body, resp.Body); err != nil {
+		logger.WarnContext(ctx, "Failed to read response body", "error", err)
+		body.Discard()
+	}
+	result.BodyBlobKey = body.Finish()
+	result.BodySize = body.Size()
+	result.BodySHA256 = body.SHA256()
+	result.BodyTruncated = body.Truncated()
+
+	// Calculate duration
+	result.Timing = trace.Timing(time.Now())
+	result.DurationMs = time.Since(startTime).Milliseconds()
+	result.ResponseBody = rendered.Redact(body.Text())
 
 	// Store response headers as JSON
 	if resp.Header != nil {
-		headersJSON, err := json.Marshal(resp.Header)
+		headersJSON, err := json.Marshal(storedHeaders(resp.Header, task.Storage))
 		if err != nil {
-			log.Printf("Failed to marshal response headers: %v", err)
+			logger.ErrorContext(ctx, "Failed to marshal response headers", "error", err)
 			result.ResponseHeaders = json.RawMessage("null")
 		} else {
-			result.ResponseHeaders = json.RawMessage(headersJSON)
+			result.ResponseHeaders = json.RawMessage(rendered.Redact(string(headersJSON)))
 		}
 	} else {
 		result.ResponseHeaders = json.RawMessage("null")
 	}
 
 	// Save result
-	e.saveResult(result)
+	e.saveResult(ctx, task, result)
+
+	logger.InfoContext(ctx, "Task executed",
+		"status_code", result.StatusCode, "success", result.Success, "duration_ms", result.DurationMs)
+}
+
+// send builds, authenticates, signs and performs the HTTP request for a
+// rendered action. rejectedToken, if set, is an access token the target
+// rejected, which is not used again. On failure it reports which stage failed.
+func (e *Executor) send(ctx context.Context, task *models.Task, rendered *RenderedRequest, trace *requestTrace, rejectedToken string) (resp *http.Response, errorType models.ErrorType, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "HTTP "+rendered.Method,
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(rendered.Method),
+			semconv.URLFull(rendered.Redact(rendered.URL)),
+		),
+	)
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, resp.Status)
+			}
+		}
+		tracing.End(span, err)
+	}()
+
+	// Prepare HTTP request
+	var reqBody io.Reader
+	if rendered.Body != "" {
+		reqBody = strings.NewReader(rendered.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rendered.Method, rendered.URL, reqBody)
+	if err != nil {
+		return nil, models.ErrorTypeRequest, fmt.Errorf("Failed to create request: %v", err)
+	}
+
+	// Set headers
+	for key, value := range rendered.Headers {
+		req.Header.Set(key, value)
+	}
+
+	// Set default Content-Type if payload exists
+	if rendered.Body != "" && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := e.client
+	if task.Action.TLSProfile != "" {
+		client, err = e.tlsClient(ctx, task.Action.TLSProfile)
+		if err != nil {
+			return nil, models.ErrorTypeTLS, fmt.Errorf("Failed to configure TLS: %v", err)
+		}
+	}
+
+	// Attach OAuth2 bearer token, fetched with the task's TLS settings
+	if task.Action.AuthProfile != "" {
+		if rejectedToken != "" {
+			e.tokens.Invalidate(task.Action.AuthProfile, rejectedToken)
+		}
+		token, err := e.authToken(ctx, client, task.Action.AuthProfile)
+		if err != nil {
+			if errors.Is(err, ErrEgressBlocked) {
+				return nil, models.ErrorTypePolicy, fmt.Errorf("Token request blocked: %v", err)
+			}
+			return nil, models.ErrorTypeAuth, fmt.Errorf("Failed to obtain access token: %v", err)
+		}
+		rendered.addSecret(token)
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// Propagate the trace to the target
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	// Sign request
+	if err := signRequest(req, []byte(rendered.Body), task.Action.Signing, e.secrets); err != nil {
+		return nil, models.ErrorTypeSigning, fmt.Errorf("Failed to sign request: %v", err)
+	}
+
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+	trace.begin()
+	resp, err = client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrEgressBlocked) {
+			return nil, models.ErrorTypePolicy, fmt.Errorf("Request blocked: %v", err)
+		}
+		return nil, models.ErrorTypeRequest, fmt.Errorf("Request failed: %v", err)
+	}
+	return resp, "", nil
+}
+
+func (e *Executor) tlsClient(ctx context.Context, profileName string) (*http.Client, error) {
+	profile, err := e.repo.WithContext(ctx).GetTLSProfileByName(profileName)
+	if err != nil {
+		return nil, fmt.Errorf("tls profile %q: %w", profileName, err)
+	}
+
+	// The client key lives in a secret, so the pooled client also has to be
+	// rebuilt when that secret is replaced
+	var keyUpdatedAt time.Time
+	if profile.ClientKeySecret != "" {
+		secret, err := e.repo.WithContext(ctx).GetSecretByName(profile.ClientKeySecret)
+		if err != nil {
+			return nil, fmt.Errorf("tls profile %q: client_key_secret: %w", profileName, err)
+		}
+		keyUpdatedAt = secret.UpdatedAt
+	}
+	return e.tlsClients.Client(profile, keyUpdatedAt)
+}
 
-	log.Printf("Task executed: %s, Status: %d, Success: %v, Duration: %dms",
-		task.Name, result.StatusCode, result.Success, result.DurationMs)
+// bodyBlobKey returns where a result's offloaded body is stored.
+func bodyBlobKey(result *models.TaskResult) string {
+	return result.RunAt.UTC().Format("2006/01/02") + "/" + result.ID.String() + ".body.gz"
 }
 
-func (e *Executor) saveResult(result *models.TaskResult) {
-	if err := e.repo.CreateTaskResult(result); err != nil {
-		log.Printf("Failed to save task result: %v", err)
+// storedHeaders applies the storage policy's header allow-list, if any.
+func storedHeaders(header http.Header, policy *models.StoragePolicy) http.Header {
+	if policy == nil || len(policy.Headers) == 0 {
+		return header
 	}
-}
\ No newline at end of file
+	filtered := make(http.Header, len(policy.Headers))
+	for _, name := range policy.Headers {
+		if values := header.Values(name); len(values) > 0 {
+			filtered[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return filtered
+}
+
+// requestHost returns the host (with port, if any) that a request targets.
+func requestHost(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Host)
+}
+
+func (e *Executor) authToken(ctx context.Context, client *http.Client, profileName string) (string, error) {
+	profile, err := e.repo.WithContext(ctx).GetAuthProfileByName(profileName)
+	if err != nil {
+		return "", fmt.Errorf("auth profile %q: %w", profileName, err)
+	}
+	return e.tokens.Token(ctx, client, profile)
+}
+
+func (e *Executor) fail(ctx context.Context, task *models.Task, result *models.TaskResult, startTime time.Time, errorType models.ErrorType, errorMsg string) {
+	result.Success = false
+	result.ErrorType = errorType
+	result.ErrorMessage = &errorMsg
+	result.DurationMs = time.Since(startTime).Milliseconds()
+	e.saveResult(ctx, task, result)
+
+	logger.WarnContext(ctx, "Task failed", "error_type", errorType, "error", errorMsg, "duration_ms", result.DurationMs)
+}
+
+func (e *Executor) saveResult(ctx context.Context, task *models.Task, result *models.TaskResult) {
+	outcome := executionOutcome(result)
+	metrics.ObserveExecution(task.ID.String(), outcome, result.StatusCode, time.Duration(result.DurationMs)*time.Millisecond)
+
+	span := oteltrace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("task.outcome", outcome))
+	if !result.Success {
+		span.SetStatus(codes.Error, outcome)
+	}
+
+	counters, err := e.repo.WithContext(ctx).CreateTaskResult(result, task.Storage)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to save task result", "error", err)
+		return
+	}
+
+	// Alerts are delivered in the background so a slow channel doesn't hold
+	// up the worker or the task's next run
+	if e.observer != nil {
+		go e.observer.Observe(context.WithoutCancel(ctx), task, result, counters)
+	}
+}
+
+// executionOutcome is "success", the error type for runs that failed before
+// a response, or "http_error" for non-2xx responses.
+func executionOutcome(result *models.TaskResult) string {
+	switch {
+	case result.Success:
+		return "success"
+	case result.ErrorType != "":
+		return string(result.ErrorType)
+	default:
+		return "http_error"
+	}
+}
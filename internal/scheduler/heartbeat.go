@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/robfig/cron/v3"
+)
+
+// cronParser parses cron triggers the same way the scheduler's cron runner does.
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ExpectedRun returns when a task should next run, counting from its last run
+// or from when it was last updated, whichever is later, so that a task that
+// was paused or changed isn't expected to have run in the meantime. A
+// heartbeat interval takes precedence over the trigger. It reports false when
+// no run is expected, such as for a one-off task that has already run.
+func ExpectedRun(task *models.Task, lastRun *time.Time) (time.Time, bool) {
+	if task.Trigger.Type == models.TriggerOneOff && lastRun != nil {
+		return time.Time{}, false
+	}
+
+	from := task.CreatedAt
+	if task.UpdatedAt.After(from) {
+		from = task.UpdatedAt
+	}
+	if lastRun != nil && lastRun.After(from) {
+		from = *lastRun
+	}
+	if task.Heartbeat != nil && task.Heartbeat.IntervalSeconds > 0 {
+		return from.Add(time.Duration(task.Heartbeat.IntervalSeconds) * time.Second), true
+	}
+
+	switch task.Trigger.Type {
+	case models.TriggerCron:
+		if task.Trigger.Cron == nil {
+			return time.Time{}, false
+		}
+		schedule, err := cronParser.Parse(*task.Trigger.Cron)
+		if err != nil {
+			return time.Time{}, false
+		}
+		// Evaluated in the cron runner's location, not the database's
+		return schedule.Next(from.In(time.Local)), true
+	case models.TriggerOneOff:
+		if task.NextRun != nil {
+			return *task.NextRun, true
+		}
+		if task.Trigger.DateTime != nil {
+			return *task.Trigger.DateTime, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+func TestExpectedRun(t *testing.T) {
+	// The cron runner uses time.Local; pin it so the cron cases don't depend
+	// on the machine's zone
+	local := time.Local
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		v := base.Add(time.Duration(minutes) * time.Minute)
+		return &v
+	}
+	daily := "0 0 9 * * *"
+	heartbeat := &models.HeartbeatPolicy{IntervalSeconds: 3600}
+
+	tests := []struct {
+		name    string
+		task    models.Task
+		lastRun *time.Time
+		want    time.Time
+		wantOK  bool
+	}{
+		{
+			name:   "heartbeat before the first run",
+			task:   models.Task{CreatedAt: base, UpdatedAt: base, Heartbeat: heartbeat},
+			want:   base.Add(time.Hour),
+			wantOK: true,
+		},
+		{
+			name:    "heartbeat from the last run",
+			task:    models.Task{CreatedAt: base, UpdatedAt: base, Heartbeat: heartbeat},
+			lastRun: at(30),
+			want:    base.Add(90 * time.Minute),
+			wantOK:  true,
+		},
+		{
+			name:    "resumed after the last run",
+			task:    models.Task{CreatedAt: base, UpdatedAt: *at(600), Heartbeat: heartbeat},
+			lastRun: at(30),
+			want:    base.Add(11 * time.Hour),
+			wantOK:  true,
+		},
+		{
+			name: "cron in the runner's location",
+			task: models.Task{CreatedAt: base, UpdatedAt: base, Trigger: models.Trigger{
+				Type: models.TriggerCron, Cron: &daily,
+			}},
+			lastRun: at(0),
+			// 10:00 UTC is 15:00 in UTC+5, so the next 09:00 there is
+			// 04:00 UTC the next day
+			want:   time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name: "invalid cron",
+			task: models.Task{CreatedAt: base, UpdatedAt: base, Trigger: models.Trigger{
+				Type: models.TriggerCron, Cron: new(string),
+			}},
+		},
+		{
+			name: "one-off before it runs",
+			task: models.Task{CreatedAt: base, UpdatedAt: base, Trigger: models.Trigger{
+				Type: models.TriggerOneOff, DateTime: at(60),
+			}},
+			want:   base.Add(time.Hour),
+			wantOK: true,
+		},
+		{
+			name: "one-off that has run",
+			task: models.Task{CreatedAt: base, UpdatedAt: base, Trigger: models.Trigger{
+				Type: models.TriggerOneOff, DateTime: at(60),
+			}},
+			lastRun: at(60),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExpectedRun(&tt.task, tt.lastRun)
+			if ok != tt.wantOK {
+				t.Fatalf("ExpectedRun() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("ExpectedRun() = %v, want %v", got.UTC(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// tokenRefreshMargin is how long before expiry a cached token is refreshed.
+const tokenRefreshMargin = 30 * time.Second
+
+// maxTokenErrorDetail is how much of a failed token response is kept in the
+// error, which ends up in the task's result.
+const maxTokenErrorDetail = 256
+
+type cachedToken struct {
+	accessToken string
+	expiresAt   time.Time
+	version     time.Time
+}
+
+// tokenEntry holds one auth profile's token. Its lock is held while the
+// token is fetched, so concurrent runs using the profile share one fetch.
+type tokenEntry struct {
+	mu    sync.Mutex
+	token *cachedToken
+}
+
+// tokenCache fetches and caches OAuth2 client-credentials tokens per auth profile.
+type tokenCache struct {
+	secrets SecretResolver
+	mu      sync.Mutex
+	entries map[string]*tokenEntry
+}
+
+func newTokenCache(secrets SecretResolver) *tokenCache {
+	return &tokenCache{
+		secrets: secrets,
+		entries: make(map[string]*tokenEntry),
+	}
+}
+
+func (c *tokenCache) entry(profileName string) *tokenEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[profileName]
+	if !ok {
+		entry = &tokenEntry{}
+		c.entries[profileName] = entry
+	}
+	return entry
+}
+
+// Token returns a valid access token for the profile, fetching a new one
+// through client when the cached token is missing, stale or close to expiry.
+// A fetch only holds up runs that use the same profile.
+func (c *tokenCache) Token(ctx context.Context, client *http.Client, profile *models.AuthProfile) (string, error) {
+	entry := c.entry(profile.Name)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	cached := entry.token
+	if cached != nil && cached.version.Equal(profile.UpdatedAt) && time.Now().Add(tokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.accessToken, nil
+	}
+
+	token, err := c.fetch(ctx, client, profile)
+	if err != nil {
+		entry.token = nil
+		return "", err
+	}
+	entry.token = &token
+	return token.accessToken, nil
+}
+
+// Invalidate drops the cached token if it is still rejected, the token a
+// target turned down, so the next call fetches a fresh one. A token another
+// run has already replaced it with is kept.
+func (c *tokenCache) Invalidate(profileName, rejected string) {
+	entry := c.entry(profileName)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.token != nil && entry.token.accessToken == rejected {
+		entry.token = nil
+	}
+}
+
+func (c *tokenCache) fetch(ctx context.Context, client *http.Client, profile *models.AuthProfile) (cachedToken, error) {
+	if c.secrets == nil {
+		return cachedToken{}, fmt.Errorf("client secret cannot be resolved: secrets store is not configured")
+	}
+	clientSecret, err := c.secrets.ResolveSecret(profile.ClientSecretName)
+	if err != nil {
+		return cachedToken{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(profile.Scopes) > 0 {
+		form.Set("scope", strings.Join(profile.Scopes, " "))
+	}
+	if profile.Audience != "" {
+		form.Set("audience", profile.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, profile.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return cachedToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(profile.ClientID), url.QueryEscape(clientSecret))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return cachedToken{}, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return cachedToken{}, fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, tokenErrorDetail(body, clientSecret))
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return cachedToken{}, fmt.Errorf("invalid token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return cachedToken{}, fmt.Errorf("token response has no access_token")
+	}
+
+	expiresIn := time.Duration(tokenResp.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = time.Hour
+	}
+
+	return cachedToken{
+		accessToken: tokenResp.AccessToken,
+		expiresAt:   time.Now().Add(expiresIn),
+		version:     profile.UpdatedAt,
+	}, nil
+}
+
+// tokenErrorDetail summarizes a failed token response: its OAuth2 error and
+// description if it has them, or else the start of the body, with the client
+// secret redacted in case the endpoint echoes it.
+func tokenErrorDetail(body []byte, clientSecret string) string {
+	detail := strings.TrimSpace(string(body))
+	var errResp struct {
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error != "" {
+		detail = errResp.Error
+		if errResp.Description != "" {
+			detail += ": " + errResp.Description
+		}
+	}
+
+	if clientSecret != "" {
+		detail = strings.ReplaceAll(detail, clientSecret, redactedValue)
+	}
+	if len(detail) > maxTokenErrorDetail {
+		detail = strings.ToValidUTF8(detail[:maxTokenErrorDetail], "") + "..."
+	}
+	return detail
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+func TestTokenErrorDetail(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "oauth error", body: `{"error":"invalid_client","error_description":"unknown client"}`, want: "invalid_client: unknown client"},
+		{name: "oauth error without description", body: `{"error":"invalid_scope"}`, want: "invalid_scope"},
+		{name: "plain text", body: "  service unavailable\n", want: "service unavailable"},
+		{name: "echoed secret", body: "bad credentials for client-secret-value", want: "bad credentials for " + redactedValue},
+		{name: "echoed secret in description", body: `{"error":"invalid_client","error_description":"client-secret-value"}`, want: "invalid_client: " + redactedValue},
+		{name: "long body", body: strings.Repeat("x", 10000), want: strings.Repeat("x", maxTokenErrorDetail) + "..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenErrorDetail([]byte(tt.body), "client-secret-value"); got != tt.want {
+				t.Errorf("tokenErrorDetail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenCacheInvalidate(t *testing.T) {
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := fetches.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, n)
+	}))
+	defer server.Close()
+
+	cache := newTokenCache(fakeSecrets{"client-secret": "s3cr3t"})
+	profile := &models.AuthProfile{Name: "partner", TokenURL: server.URL, ClientID: "client", ClientSecretName: "client-secret"}
+	token := func() string {
+		t.Helper()
+		token, err := cache.Token(context.Background(), server.Client(), profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	if got := token(); got != "token-1" {
+		t.Fatalf("Token() = %q, want token-1", got)
+	}
+
+	// A rejected token is replaced
+	cache.Invalidate(profile.Name, "token-1")
+	if got := token(); got != "token-2" {
+		t.Fatalf("Token() after invalidating it = %q, want token-2", got)
+	}
+
+	// A run that was rejected with the old token doesn't drop the new one
+	cache.Invalidate(profile.Name, "token-1")
+	if got := token(); got != "token-2" {
+		t.Errorf("Token() after invalidating an older token = %q, want token-2", got)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("token endpoint called %d times, want 2", got)
+	}
+}
@@ -0,0 +1,160 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+)
+
+// ErrThrottled is returned when a run cannot get a rate limit token or a
+// concurrency slot for its host before the wait deadline.
+var ErrThrottled = errors.New("throttled")
+
+// hostLimiter combines a token bucket and a concurrency semaphore for one rate
+// limit rule, shared by every host the rule matches.
+type hostLimiter struct {
+	mu       sync.Mutex
+	rate     float64 // tokens per second, 0 means unlimited
+	burst    float64
+	tokens   float64
+	lastFill time.Time
+	slots    chan struct{} // nil means unlimited concurrency
+}
+
+func newHostLimiter(rule config.RateLimitRule) *hostLimiter {
+	l := &hostLimiter{lastFill: time.Now()}
+	if rule.Requests > 0 && rule.Per > 0 {
+		l.rate = float64(rule.Requests) / rule.Per.Seconds()
+		l.burst = float64(rule.Burst)
+		if l.burst < 1 {
+			l.burst = math.Max(1, float64(rule.Requests))
+		}
+		l.tokens = l.burst
+	}
+	if rule.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, rule.MaxConcurrent)
+	}
+	return l
+}
+
+// reserve takes a token, returning how long the caller must wait before using
+// it. It fails without consuming anything if the wait would exceed maxWait.
+func (l *hostLimiter) reserve(maxWait time.Duration) (time.Duration, bool) {
+	if l.rate == 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.lastFill).Seconds()*l.rate)
+	l.lastFill = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+
+	wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	if wait > maxWait {
+		return 0, false
+	}
+	l.tokens--
+	return wait, true
+}
+
+// acquireSlot waits up to maxWait for a concurrency slot, returning the func
+// that frees it and how long it waited.
+func (l *hostLimiter) acquireSlot(maxWait time.Duration) (func(), time.Duration, bool) {
+	if l.slots == nil {
+		return func() {}, 0, true
+	}
+
+	release := func() { <-l.slots }
+	select {
+	case l.slots <- struct{}{}:
+		return release, 0, true
+	default:
+	}
+
+	// All slots are busy; wait for one until the deadline
+	start := time.Now()
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return release, time.Since(start), true
+	case <-timer.C:
+		return nil, 0, false
+	}
+}
+
+// rateLimiter applies limits configured by host pattern. Each rule has one
+// limiter, so all hosts matching a pattern such as *.example.com share it.
+type rateLimiter struct {
+	rules    []config.RateLimitRule
+	limiters []*hostLimiter // one per rule
+	maxWait  time.Duration
+}
+
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	limiters := make([]*hostLimiter, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		limiters[i] = newHostLimiter(rule)
+	}
+	return &rateLimiter{
+		rules:    cfg.Rules,
+		limiters: limiters,
+		maxWait:  cfg.MaxWait,
+	}
+}
+
+// limiterFor returns the limiter of the first rule matching host, or nil if
+// none does. Matches aren't cached, so the hosts tasks call cannot grow the
+// limiter's memory.
+func (r *rateLimiter) limiterFor(host string) *hostLimiter {
+	hostname := host
+	if i := strings.LastIndex(hostname, ":"); i >= 0 && !strings.HasSuffix(hostname, "]") {
+		hostname = hostname[:i]
+	}
+
+	for i, rule := range r.rules {
+		if matchHost([]string{strings.ToLower(rule.Host)}, hostname) {
+			return r.limiters[i]
+		}
+	}
+	return nil
+}
+
+// Acquire waits for a concurrency slot and then a rate limit token for host,
+// up to the configured maximum wait in all. The slot is taken first so that a
+// run that times out waiting for one doesn't use up a token. The returned
+// release func must be called when the request has finished.
+func (r *rateLimiter) Acquire(host string) (func(), time.Duration, error) {
+	l := r.limiterFor(host)
+	if l == nil {
+		return func() {}, 0, nil
+	}
+
+	release, waited, ok := l.acquireSlot(r.maxWait)
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: too many concurrent requests to host %s", ErrThrottled, host)
+	}
+
+	wait, ok := l.reserve(r.maxWait - waited)
+	if !ok {
+		release()
+		return nil, 0, fmt.Errorf("%w: rate limit for host %s exceeded", ErrThrottled, host)
+	}
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return release, waited + wait, nil
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/config"
+)
+
+func TestRateLimiterTokens(t *testing.T) {
+	limiter := newRateLimiter(config.RateLimitConfig{
+		Rules:   []config.RateLimitRule{{Host: "api.example.com", Requests: 2, Per: time.Hour}},
+		MaxWait: 10 * time.Millisecond,
+	})
+
+	for i := 0; i < 2; i++ {
+		release, waited, err := limiter.Acquire("api.example.com")
+		if err != nil {
+			t.Fatalf("Acquire() %d error = %v", i, err)
+		}
+		if waited != 0 {
+			t.Errorf("Acquire() %d waited %v, want no wait within the burst", i, waited)
+		}
+		release()
+	}
+
+	if _, _, err := limiter.Acquire("api.example.com"); !errors.Is(err, ErrThrottled) {
+		t.Fatalf("Acquire() past the burst error = %v, want %v", err, ErrThrottled)
+	}
+	if _, _, err := limiter.Acquire("other.example.com"); err != nil {
+		t.Errorf("Acquire() for an unmatched host error = %v", err)
+	}
+}
+
+func TestRateLimiterWaitsForToken(t *testing.T) {
+	limiter := newRateLimiter(config.RateLimitConfig{
+		Rules:   []config.RateLimitRule{{Host: "api.example.com", Requests: 10, Per: time.Second, Burst: 1}},
+		MaxWait: time.Second,
+	})
+
+	release, _, err := limiter.Acquire("api.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	release, waited, err := limiter.Acquire("api.example.com")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	release()
+	if waited <= 0 || waited > 100*time.Millisecond {
+		t.Errorf("Acquire() waited %v, want up to 100ms for the next token", waited)
+	}
+}
+
+func TestRateLimiterConcurrency(t *testing.T) {
+	limiter := newRateLimiter(config.RateLimitConfig{
+		Rules:   []config.RateLimitRule{{Host: "api.example.com", MaxConcurrent: 1}},
+		MaxWait: 10 * time.Millisecond,
+	})
+
+	release, _, err := limiter.Acquire("api.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := limiter.Acquire("api.example.com"); !errors.Is(err, ErrThrottled) {
+		t.Fatalf("Acquire() with every slot busy error = %v, want %v", err, ErrThrottled)
+	}
+
+	release()
+	release, _, err = limiter.Acquire("api.example.com")
+	if err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	release()
+}
+
+func TestRateLimiterSlotTimeoutKeepsToken(t *testing.T) {
+	limiter := newRateLimiter(config.RateLimitConfig{
+		Rules:   []config.RateLimitRule{{Host: "api.example.com", Requests: 2, Per: time.Hour, MaxConcurrent: 1}},
+		MaxWait: 10 * time.Millisecond,
+	})
+
+	release, _, err := limiter.Acquire("api.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := limiter.Acquire("api.example.com"); !errors.Is(err, ErrThrottled) {
+		t.Fatalf("Acquire() with every slot busy error = %v, want %v", err, ErrThrottled)
+	}
+	release()
+
+	// The run that timed out waiting for a slot didn't use the second token
+	release, _, err = limiter.Acquire("api.example.com")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v, want the token left by the timed out run", err)
+	}
+	release()
+}
+
+func TestRateLimiterSharedAcrossHosts(t *testing.T) {
+	limiter := newRateLimiter(config.RateLimitConfig{
+		Rules: []config.RateLimitRule{
+			{Host: "*.example.com", MaxConcurrent: 1},
+			{Host: "api.example.org", MaxConcurrent: 1},
+		},
+		MaxWait: 10 * time.Millisecond,
+	})
+
+	release, _, err := limiter.Acquire("a.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	if _, _, err := limiter.Acquire("b.example.com:8443"); !errors.Is(err, ErrThrottled) {
+		t.Errorf("Acquire() for another host of the same rule error = %v, want %v", err, ErrThrottled)
+	}
+	other, _, err := limiter.Acquire("api.example.org")
+	if err != nil {
+		t.Fatalf("Acquire() for another rule's host error = %v", err)
+	}
+	other()
+}
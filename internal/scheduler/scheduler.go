@@ -1,16 +1,35 @@
 package scheduler
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
+	"github.com/ayushsarode/task-scheduler/internal/config"
 	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/logging"
 	"github.com/ayushsarode/task-scheduler/internal/models"
 )
 
+var logger = logging.For("scheduler")
+
+const (
+	// oneOffInterval is how often the one-off worker looks for due tasks
+	oneOffInterval = 10 * time.Second
+	// heartbeatInterval is how often the cron runner records that it is alive
+	heartbeatInterval = 5 * time.Second
+	// staleIntervals is how many intervals may pass without a tick before
+	// the scheduler is reported as stalled
+	staleIntervals = 3
+)
+
 type Scheduler struct {
 	cron     *cron.Cron
 	repo     *db.Repository
@@ -18,42 +37,63 @@ type Scheduler struct {
 	jobs     map[uuid.UUID]cron.EntryID
 	mu       sync.RWMutex
 	stopCh   chan struct{}
+
+	running  atomic.Bool
+	loopTick atomic.Int64
+	cronTick atomic.Int64
 }
 
-func NewScheduler(repo *db.Repository) *Scheduler {
+func NewScheduler(repo *db.Repository, secrets SecretResolver, blobs *blobstore.FSStore, observer ResultObserver, cfg config.ExecutorConfig) (*Scheduler, error) {
+	executor, err := NewExecutor(repo, secrets, blobs, observer, cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Scheduler{
 		cron:     cron.New(cron.WithSeconds()),
 		repo:     repo,
-		executor: NewExecutor(repo),
+		executor: executor,
 		jobs:     make(map[uuid.UUID]cron.EntryID),
 		stopCh:   make(chan struct{}),
-	}
+	}, nil
 }
 
 func (s *Scheduler) Start() error {
-	log.Println("Starting scheduler...")
+	logger.Info("Starting scheduler")
 
 	// Load existing scheduled tasks
 	if err := s.loadTasks(); err != nil {
 		return err
 	}
 
+	// Have the cron runner record a heartbeat so readiness can tell it is alive
+	if _, err := s.cron.AddFunc(fmt.Sprintf("@every %s", heartbeatInterval), func() {
+		s.cronTick.Store(time.Now().UnixNano())
+	}); err != nil {
+		return err
+	}
+	now := time.Now().UnixNano()
+	s.loopTick.Store(now)
+	s.cronTick.Store(now)
+
 	// Start cron scheduler
 	s.cron.Start()
 
 	// Start background worker for one-off tasks
 	go s.runOneOffWorker()
+	s.running.Store(true)
 
-	log.Println("Scheduler started successfully")
+	logger.Info("Scheduler started")
 	return nil
 }
 
 func (s *Scheduler) Stop() {
-	log.Println("Stopping scheduler...")
+	logger.Info("Stopping scheduler")
+	s.running.Store(false)
 	close(s.stopCh)
 	ctx := s.cron.Stop()
 	<-ctx.Done()
-	log.Println("Scheduler stopped")
+	logger.Info("Scheduler stopped")
 }
 
 func (s *Scheduler) loadTasks() error {
@@ -62,11 +102,11 @@ func (s *Scheduler) loadTasks() error {
 		return err
 	}
 
-	log.Printf("Loading %d scheduled tasks", len(tasks))
+	logger.Info("Loading scheduled tasks", "count", len(tasks))
 
 	for _, task := range tasks {
 		if err := s.ScheduleTask(&task); err != nil {
-			log.Printf("Failed to schedule task %s: %v", task.ID, err)
+			logger.Error("Failed to schedule task", "task_id", task.ID, "error", err)
 		}
 	}
 
@@ -83,13 +123,18 @@ func (s *Scheduler) ScheduleTask(task *models.Task) error {
 		delete(s.jobs, task.ID)
 	}
 
+	// Check-in tasks are fed by pings; nothing runs on their schedule
+	if task.Type == models.TaskTypeCheckin {
+		return nil
+	}
+
 	switch task.Trigger.Type {
 	case models.TriggerCron:
 		return s.scheduleCronTask(task)
 	case models.TriggerOneOff:
 		return s.scheduleOneOffTask(task)
 	default:
-		log.Printf("Unknown trigger type: %s", task.Trigger.Type)
+		logger.Warn("Unknown trigger type", "task_id", task.ID, "trigger", task.Trigger.Type)
 	}
 
 	return nil
@@ -97,13 +142,19 @@ func (s *Scheduler) ScheduleTask(task *models.Task) error {
 
 func (s *Scheduler) scheduleCronTask(task *models.Task) error {
 	if task.Trigger.Cron == nil {
-		log.Printf("Cron expression is nil for task %s", task.ID)
+		logger.Warn("Cron expression is nil", "task_id", task.ID)
 		return nil
 	}
 
 	cronExpr := *task.Trigger.Cron
+	var entryID cron.EntryID
 	entryID, err := s.cron.AddFunc(cronExpr, func() {
-		s.executor.ExecuteTask(task)
+		// The runner sets Prev to the activation time before starting the job
+		scheduledAt := s.cron.Entry(entryID).Prev
+		if scheduledAt.IsZero() {
+			scheduledAt = time.Now().Truncate(time.Second)
+		}
+		s.executor.ExecuteTask(task, scheduledAt)
 	})
 
 	if err != nil {
@@ -117,40 +168,41 @@ func (s *Scheduler) scheduleCronTask(task *models.Task) error {
 	nextRun := entry.Next
 	task.NextRun = &nextRun
 
-	// Update task in database
-	if err := s.repo.UpdateTask(task); err != nil {
-		log.Printf("Failed to update next_run for task %s: %v", task.ID, err)
+	// Only next_run is written, so a concurrent edit of the task isn't undone
+	if err := s.repo.SetTaskNextRun(task); err != nil {
+		logger.Error("Failed to update next_run", "task_id", task.ID, "error", err)
 	}
 
-	log.Printf("Scheduled cron task: %s (%s), next run: %s", task.Name, cronExpr, nextRun)
+	logger.Info("Scheduled cron task", "task_id", task.ID, "task_name", task.Name, "cron", cronExpr, "next_run", nextRun)
 	return nil
 }
 
 func (s *Scheduler) scheduleOneOffTask(task *models.Task) error {
 	if task.Trigger.DateTime == nil {
-		log.Printf("DateTime is nil for one-off task %s", task.ID)
+		logger.Warn("DateTime is nil for one-off task", "task_id", task.ID)
 		return nil
 	}
 
 	scheduledTime := *task.Trigger.DateTime
 	task.NextRun = &scheduledTime
 
-	// Update task in database
-	if err := s.repo.UpdateTask(task); err != nil {
-		log.Printf("Failed to update next_run for task %s: %v", task.ID, err)
+	// Only next_run is written, so a concurrent edit of the task isn't undone
+	if err := s.repo.SetTaskNextRun(task); err != nil {
+		logger.Error("Failed to update next_run", "task_id", task.ID, "error", err)
 	}
 
-	log.Printf("Scheduled one-off task: %s at %s", task.Name, scheduledTime)
+	logger.Info("Scheduled one-off task", "task_id", task.ID, "task_name", task.Name, "scheduled_at", scheduledTime)
 	return nil
 }
 
 func (s *Scheduler) runOneOffWorker() {
-	ticker := time.NewTicker(10 * time.Second)
+	ticker := time.NewTicker(oneOffInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
+			s.loopTick.Store(time.Now().UnixNano())
 			s.checkOneOffTasks()
 		case <-s.stopCh:
 			return
@@ -161,27 +213,27 @@ func (s *Scheduler) runOneOffWorker() {
 func (s *Scheduler) checkOneOffTasks() {
 	tasks, err := s.repo.GetScheduledTasks()
 	if err != nil {
-		log.Printf("Failed to get scheduled tasks: %v", err)
+		logger.Error("Failed to get scheduled tasks", "error", err)
 		return
 	}
 
 	now := time.Now()
 
 	for _, task := range tasks {
-		if task.Trigger.Type == models.TriggerOneOff &&
+		if task.Type != models.TaskTypeCheckin &&
+			task.Trigger.Type == models.TriggerOneOff &&
 			task.NextRun != nil &&
 			task.NextRun.Before(now) {
 
-			// Execute task
-			go s.executor.ExecuteTask(&task)
-
-			// Mark task as completed
-			task.Status = models.StatusCompleted
-			task.UpdatedAt = time.Now()
-			task.NextRun = nil
-
-			if err := s.repo.UpdateTask(&task); err != nil {
-				log.Printf("Failed to update task status: %v", err)
+			// Mark task as completed first, so a task paused or cancelled
+			// since it was read doesn't run
+			completed, err := s.repo.CompleteTask(task.ID, time.Now())
+			if err != nil {
+				logger.Error("Failed to update task status", "task_id", task.ID, "error", err)
+				continue
+			}
+			if completed {
+				go s.executor.ExecuteTask(&task, *task.NextRun)
 			}
 
 			// Remove from jobs map
@@ -199,6 +251,99 @@ func (s *Scheduler) RemoveTask(taskID uuid.UUID) {
 	if entryID, exists := s.jobs[taskID]; exists {
 		s.cron.Remove(entryID)
 		delete(s.jobs, taskID)
-		log.Printf("Removed task from scheduler: %s", taskID)
+		logger.Info("Removed task from scheduler", "task_id", taskID)
+	}
+}
+
+// JobCount returns the number of registered cron entries.
+func (s *Scheduler) JobCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.jobs)
+}
+
+// Liveness reports when the one-off worker and the cron runner last ticked.
+// It returns an error when the scheduler is not running or either of them
+// has missed several ticks.
+func (s *Scheduler) Liveness() (map[string]interface{}, error) {
+	details := map[string]interface{}{
+		"cron_entries": s.JobCount(),
+	}
+	if !s.running.Load() {
+		return details, errors.New("scheduler is not running")
+	}
+
+	now := time.Now()
+	loopTick := time.Unix(0, s.loopTick.Load())
+	cronTick := time.Unix(0, s.cronTick.Load())
+	details["last_loop_tick"] = loopTick
+	details["last_cron_tick"] = cronTick
+
+	if age := now.Sub(loopTick); age > staleIntervals*oneOffInterval {
+		return details, fmt.Errorf("one-off worker last ticked %s ago", age.Round(time.Second))
 	}
-}
\ No newline at end of file
+	if age := now.Sub(cronTick); age > staleIntervals*heartbeatInterval {
+		return details, fmt.Errorf("cron runner last ticked %s ago", age.Round(time.Second))
+	}
+	return details, nil
+}
+
+// ValidateAction checks an action's templates and verifies that any
+// referenced secrets and auth profile exist. Secret values are never
+// decrypted here; they are only read when the task runs.
+func (s *Scheduler) ValidateAction(action models.Action) error {
+	var secrets SecretResolver
+	if s.executor.secrets != nil {
+		secrets = secretNameChecker{repo: s.repo}
+	}
+	if err := ValidateAction(action, secrets); err != nil {
+		return err
+	}
+
+	// Reject literal destinations the egress policy would block at dial time
+	if parsed, err := url.Parse(action.URL); err == nil && !strings.Contains(parsed.Host, "{{") {
+		if err := s.executor.egress.CheckHost(parsed.Hostname()); err != nil {
+			return err
+		}
+	}
+
+	if action.AuthProfile != "" {
+		if _, err := s.repo.GetAuthProfileByName(action.AuthProfile); err != nil {
+			return fmt.Errorf("auth profile %q not found", action.AuthProfile)
+		}
+	}
+
+	if action.TLSProfile != "" {
+		if _, err := s.repo.GetTLSProfileByName(action.TLSProfile); err != nil {
+			return fmt.Errorf("tls profile %q not found", action.TLSProfile)
+		}
+	}
+
+	return nil
+}
+
+// CircuitBreakers returns the state of every per-host circuit breaker.
+func (s *Scheduler) CircuitBreakers() []models.CircuitBreakerState {
+	return s.executor.breakers.States()
+}
+
+// ResetCircuitBreaker closes the breaker for host. It reports whether one existed.
+func (s *Scheduler) ResetCircuitBreaker(host string) bool {
+	return s.executor.breakers.Reset(strings.ToLower(host))
+}
+
+// secretPlaceholder stands in for secret values while actions are validated.
+const secretPlaceholder = "secret"
+
+// secretNameChecker resolves every secret that exists to a placeholder, so
+// that validation can check secret names without decrypting their values.
+type secretNameChecker struct {
+	repo *db.Repository
+}
+
+func (c secretNameChecker) ResolveSecret(name string) (string, error) {
+	if _, err := c.repo.GetSecretByName(name); err != nil {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+	return secretPlaceholder, nil
+}
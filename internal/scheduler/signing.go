@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/pkg/signature"
+)
+
+// validateSigning checks the signing configuration and that its secret
+// exists. Ed25519 key material is only parsed when a request is signed.
+func validateSigning(cfg *models.SigningConfig, resolver SecretResolver) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.Secret == "" {
+		return fmt.Errorf("signing secret is required")
+	}
+
+	switch cfg.Type {
+	case models.SigningHMAC:
+	case models.SigningMessageSignature:
+		switch signingAlgorithm(cfg) {
+		case signature.AlgHMACSHA256, signature.AlgEd25519:
+		default:
+			return fmt.Errorf("unsupported signing algorithm: %s", cfg.Algorithm)
+		}
+	default:
+		return fmt.Errorf("invalid signing type: %s", cfg.Type)
+	}
+
+	if resolver == nil {
+		return fmt.Errorf("signing requires the secrets store to be configured")
+	}
+	if _, err := resolver.ResolveSecret(cfg.Secret); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// signRequest adds signature headers to req using key material from the secrets store.
+func signRequest(req *http.Request, body []byte, cfg *models.SigningConfig, resolver SecretResolver) error {
+	if cfg == nil {
+		return nil
+	}
+	if resolver == nil {
+		return fmt.Errorf("signing requires the secrets store to be configured")
+	}
+
+	keyMaterial, err := resolver.ResolveSecret(cfg.Secret)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch cfg.Type {
+	case models.SigningHMAC:
+		signature.SignHMAC(req, body, []byte(keyMaterial), now)
+		return nil
+	case models.SigningMessageSignature:
+		key := signature.Key{
+			ID:        cfg.KeyID,
+			Algorithm: signingAlgorithm(cfg),
+		}
+		if key.ID == "" {
+			key.ID = cfg.Secret
+		}
+		if key.Algorithm == signature.AlgEd25519 {
+			if key.Private, err = parseEd25519Key(keyMaterial); err != nil {
+				return err
+			}
+		} else {
+			key.Secret = []byte(keyMaterial)
+		}
+		return signature.SignMessage(req, body, key, now)
+	default:
+		return fmt.Errorf("invalid signing type: %s", cfg.Type)
+	}
+}
+
+func signingAlgorithm(cfg *models.SigningConfig) string {
+	if cfg.Algorithm == "" {
+		return signature.AlgHMACSHA256
+	}
+	return cfg.Algorithm
+}
+
+// parseEd25519Key accepts a base64-encoded 32-byte seed or 64-byte private key.
+func parseEd25519Key(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("ed25519 signing key must be base64-encoded: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("ed25519 signing key must be a 32-byte seed or 64-byte private key")
+	}
+}
@@ -0,0 +1,376 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+// TemplateData is the set of variables available to action templates at fire time.
+type TemplateData struct {
+	TaskID        uuid.UUID
+	TaskName      string
+	RunID         uuid.UUID
+	ScheduledTime time.Time
+	Now           time.Time
+	Attempt       int
+	Vars          map[string]string
+}
+
+// SecretResolver looks up the plaintext value of a named secret.
+type SecretResolver interface {
+	ResolveSecret(name string) (string, error)
+}
+
+const redactedValue = "[REDACTED]"
+
+// RenderedRequest is the outgoing HTTP request after templates have been applied.
+type RenderedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+
+	secrets []string
+}
+
+// addSecret registers a value that must be redacted from stored results,
+// along with its URL-escaped forms, which is how it appears once the URL has
+// been parsed and printed again.
+func (r *RenderedRequest) addSecret(value string) {
+	r.secrets = append(r.secrets, value)
+	for _, escaped := range []string{url.QueryEscape(value), url.PathEscape(value)} {
+		if escaped != value {
+			r.secrets = append(r.secrets, escaped)
+		}
+	}
+	// Longest first, so a secret that contains another is replaced whole
+	sort.SliceStable(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+}
+
+// Redact replaces every secret value resolved while rendering with a placeholder.
+func (r *RenderedRequest) Redact(s string) string {
+	for _, value := range r.secrets {
+		if value != "" {
+			s = strings.ReplaceAll(s, value, redactedValue)
+		}
+	}
+	return s
+}
+
+// SecretValues returns the secret values resolved while rendering.
+func (r *RenderedRequest) SecretValues() []string {
+	return r.secrets
+}
+
+// Redacted returns a copy of the request that is safe to store.
+func (r *RenderedRequest) Redacted() *RenderedRequest {
+	redacted := &RenderedRequest{
+		Method: r.Method,
+		URL:    r.Redact(r.URL),
+		Body:   r.Redact(r.Body),
+	}
+	if len(r.Headers) > 0 {
+		redacted.Headers = make(map[string]string, len(r.Headers))
+		for key, value := range r.Headers {
+			redacted.Headers[key] = r.Redact(value)
+		}
+	}
+	return redacted
+}
+
+var templateFuncs = template.FuncMap{
+	"addDays": func(days int, t time.Time) time.Time {
+		return t.AddDate(0, 0, days)
+	},
+	"addMonths": func(months int, t time.Time) time.Time {
+		return t.AddDate(0, months, 0)
+	},
+	"addHours": func(hours int, t time.Time) time.Time {
+		return t.Add(time.Duration(hours) * time.Hour)
+	},
+	"addMinutes": func(minutes int, t time.Time) time.Time {
+		return t.Add(time.Duration(minutes) * time.Minute)
+	},
+	"addDuration": func(d string, t time.Time) (time.Time, error) {
+		duration, err := time.ParseDuration(d)
+		if err != nil {
+			return t, err
+		}
+		return t.Add(duration), nil
+	},
+	"startOfDay": func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	},
+	"startOfMonth": func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	},
+	"inZone": func(name string, t time.Time) (time.Time, error) {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return t, err
+		}
+		return t.In(loc), nil
+	},
+	"utc": func(t time.Time) time.Time {
+		return t.UTC()
+	},
+	"formatTime": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"rfc3339": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+	"unix": func(t time.Time) int64 {
+		return t.Unix()
+	},
+	"jsonEscape": func(s string) string {
+		b, _ := json.Marshal(s)
+		return string(b[1 : len(b)-1])
+	},
+	"urlquery": url.QueryEscape,
+}
+
+// RenderAction applies the action's templates to the URL, headers and payload.
+// Secrets referenced with {{ secret "name" }} are looked up through resolver.
+// A secret may only be inserted as is: redaction matches the exact value, so
+// a transformed secret would reach stored results and traces unredacted.
+func RenderAction(action models.Action, data TemplateData, resolver SecretResolver) (*RenderedRequest, error) {
+	if data.Vars == nil {
+		data.Vars = action.Variables
+	}
+
+	rendered := &RenderedRequest{Method: action.Method}
+	funcs := template.FuncMap{
+		"secret": func(name string) (string, error) {
+			if resolver == nil {
+				return "", fmt.Errorf("secret %q cannot be resolved: secrets store is not configured", name)
+			}
+			value, err := resolver.ResolveSecret(name)
+			if err != nil {
+				return "", err
+			}
+			rendered.addSecret(value)
+			return value, nil
+		},
+	}
+
+	var err error
+	if rendered.URL, err = renderString("url", action.URL, data, funcs); err != nil {
+		return nil, err
+	}
+
+	if len(action.Headers) > 0 {
+		rendered.Headers = make(map[string]string, len(action.Headers))
+		for key, value := range action.Headers {
+			if rendered.Headers[key], err = renderString("header "+key, value, data, funcs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(action.Payload) > 0 {
+		if rendered.Body, err = renderString("payload", string(action.Payload), data, funcs); err != nil {
+			return nil, err
+		}
+		if !json.Valid([]byte(rendered.Body)) {
+			return nil, fmt.Errorf("rendered payload is not valid JSON")
+		}
+	}
+
+	return rendered, nil
+}
+
+// ValidateAction parses the action's templates and renders them with sample
+// data so template errors and unknown secrets surface when the task is
+// created rather than at fire time. resolver only needs to report unknown
+// secrets; the values it returns are discarded.
+func ValidateAction(action models.Action, resolver SecretResolver) error {
+	now := time.Now()
+	rendered, err := RenderAction(action, TemplateData{
+		TaskID:        uuid.New(),
+		TaskName:      "validation",
+		RunID:         uuid.New(),
+		ScheduledTime: now,
+		Now:           now,
+		Attempt:       1,
+	}, resolver)
+	if err != nil {
+		return err
+	}
+
+	if _, err := http.NewRequest(rendered.Method, rendered.URL, nil); err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	parsed, err := url.ParseRequestURI(rendered.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("invalid url: scheme must be http or https")
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("invalid url: host is required")
+	}
+
+	if err := validateSigning(action.Signing, resolver); err != nil {
+		return fmt.Errorf("invalid signing: %w", err)
+	}
+
+	return nil
+}
+
+func renderString(name, text string, data TemplateData, funcs template.FuncMap) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && !bareSecrets(t.Tree.Root) {
+			return "", fmt.Errorf("invalid %s template: secret must be used on its own, as {{ secret \"name\" }}", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// UsesSecrets reports whether running the action reads the secrets store:
+// through a secret in a template, a signing key, or an auth or TLS profile,
+// whose credentials are secrets too. Templates that don't parse are left to
+// ValidateAction to reject.
+func UsesSecrets(action models.Action) bool {
+	if action.Signing != nil || action.AuthProfile != "" || action.TLSProfile != "" {
+		return true
+	}
+	texts := []string{action.URL, string(action.Payload)}
+	for _, value := range action.Headers {
+		texts = append(texts, value)
+	}
+	for _, text := range texts {
+		if !strings.Contains(text, "{{") {
+			continue
+		}
+		tmpl, err := template.New("action").Funcs(templateFuncs).Funcs(template.FuncMap{"secret": func(string) string { return "" }}).Parse(text)
+		if err != nil {
+			continue
+		}
+		for _, t := range tmpl.Templates() {
+			if t.Tree == nil {
+				continue
+			}
+			found := false
+			inspect(t.Tree.Root, func(node parse.Node) bool {
+				if ident, ok := node.(*parse.IdentifierNode); ok && ident.Ident == "secret" {
+					found = true
+				}
+				return !found
+			})
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bareSecrets reports whether every call to secret under node is a whole
+// action of its own, such as {{ secret "name" }}, rather than being piped,
+// passed to another function, assigned to a variable or used in a condition.
+func bareSecrets(node parse.Node) bool {
+	ok := true
+	inspect(node, func(n parse.Node) bool {
+		if action, isAction := n.(*parse.ActionNode); isAction && isBareSecret(action.Pipe) {
+			return false
+		}
+		if ident, isIdent := n.(*parse.IdentifierNode); isIdent && ident.Ident == "secret" {
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+// isBareSecret reports whether pipe is exactly one call of secret with a
+// single argument that doesn't itself call secret.
+func isBareSecret(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Decl) > 0 || len(pipe.Cmds) != 1 {
+		return false
+	}
+	args := pipe.Cmds[0].Args
+	if len(args) != 2 {
+		return false
+	}
+	ident, ok := args[0].(*parse.IdentifierNode)
+	return ok && ident.Ident == "secret" && bareSecrets(args[1])
+}
+
+// inspect calls visit for node and then, while visit returns true, for each
+// node beneath it.
+func inspect(node parse.Node, visit func(parse.Node) bool) {
+	if node == nil || !visit(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			inspect(child, visit)
+		}
+	case *parse.ActionNode:
+		inspect(n.Pipe, visit)
+	case *parse.IfNode:
+		inspectBranch(&n.BranchNode, visit)
+	case *parse.RangeNode:
+		inspectBranch(&n.BranchNode, visit)
+	case *parse.WithNode:
+		inspectBranch(&n.BranchNode, visit)
+	case *parse.TemplateNode:
+		inspect(n.Pipe, visit)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			inspect(cmd, visit)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			inspect(arg, visit)
+		}
+	case *parse.ChainNode:
+		inspect(n.Node, visit)
+	}
+}
+
+func inspectBranch(n *parse.BranchNode, visit func(parse.Node) bool) {
+	inspect(n.Pipe, visit)
+	if n.List != nil {
+		inspect(n.List, visit)
+	}
+	if n.ElseList != nil {
+		inspect(n.ElseList, visit)
+	}
+}
@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/google/uuid"
+)
+
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) ResolveSecret(name string) (string, error) {
+	if value, ok := f[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret not found")
+}
+
+func testTemplateData() TemplateData {
+	return TemplateData{
+		TaskID:        uuid.MustParse("8b9f1c2e-0000-4000-8000-000000000001"),
+		TaskName:      "nightly",
+		RunID:         uuid.MustParse("8b9f1c2e-0000-4000-8000-000000000002"),
+		ScheduledTime: time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC),
+		Now:           time.Date(2026, 3, 14, 9, 30, 5, 0, time.UTC),
+		Attempt:       2,
+	}
+}
+
+func TestRenderAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   models.Action
+		wantURL  string
+		wantBody string
+		wantErr  bool
+	}{
+		{
+			name:    "plain url",
+			action:  models.Action{Method: "GET", URL: "https://api.example.com/ping"},
+			wantURL: "https://api.example.com/ping",
+		},
+		{
+			name:    "time helpers",
+			action:  models.Action{Method: "GET", URL: `https://api.example.com/report?day={{ date (addDays -1 .ScheduledTime) }}&at={{ unix .ScheduledTime }}`},
+			wantURL: "https://api.example.com/report?day=2026-03-13&at=1773480600",
+		},
+		{
+			name:    "variables",
+			action:  models.Action{Method: "GET", URL: "https://api.example.com/{{ .Vars.region }}", Variables: map[string]string{"region": "eu"}},
+			wantURL: "https://api.example.com/eu",
+		},
+		{
+			name:     "payload",
+			action:   models.Action{Method: "POST", URL: "https://api.example.com", Payload: json.RawMessage(`{"run":"{{ .RunID }}","attempt":{{ .Attempt }},"name":"{{ jsonEscape "a\"b" }}"}`)},
+			wantURL:  "https://api.example.com",
+			wantBody: `{"run":"8b9f1c2e-0000-4000-8000-000000000002","attempt":2,"name":"a\"b"}`,
+		},
+		{
+			name:    "payload must stay JSON",
+			action:  models.Action{Method: "POST", URL: "https://api.example.com", Payload: json.RawMessage(`{"a": {{ .TaskName }}}`)},
+			wantErr: true,
+		},
+		{
+			name:    "unknown variable",
+			action:  models.Action{Method: "GET", URL: "https://api.example.com/{{ .Vars.missing }}"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown secret",
+			action:  models.Action{Method: "GET", URL: `https://api.example.com/?k={{ secret "missing" }}`},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered, err := RenderAction(tt.action, testTemplateData(), fakeSecrets{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if rendered.URL != tt.wantURL {
+				t.Errorf("URL = %q, want %q", rendered.URL, tt.wantURL)
+			}
+			if rendered.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", rendered.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRenderActionRedactsSecrets(t *testing.T) {
+	secrets := fakeSecrets{"token": "s3cr3t value", "key": "k-123"}
+	action := models.Action{
+		Method:  "POST",
+		URL:     `https://api.example.com/hooks?key={{ secret "key" }}`,
+		Headers: map[string]string{"Authorization": `Bearer {{ secret "token" }}`},
+		Payload: json.RawMessage(`{"token":"{{ secret "token" }}"}`),
+	}
+
+	rendered, err := RenderAction(action, testTemplateData(), secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered.Headers["Authorization"] != "Bearer s3cr3t value" {
+		t.Fatalf("Authorization = %q, want the real secret", rendered.Headers["Authorization"])
+	}
+
+	redacted := rendered.Redacted()
+	stored, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{"s3cr3t", "k-123"} {
+		if strings.Contains(string(stored), value) {
+			t.Errorf("stored request contains %q: %s", value, stored)
+		}
+	}
+	if redacted.Headers["Authorization"] != "Bearer "+redactedValue {
+		t.Errorf("redacted Authorization = %q", redacted.Headers["Authorization"])
+	}
+
+	// Errors quote the URL after it has been parsed and escaped again
+	if got := rendered.Redact("request to /?t=s3cr3t+value failed"); strings.Contains(got, "s3cr3t") {
+		t.Errorf("Redact() left the query-escaped secret: %q", got)
+	}
+}
+
+func TestRedactNestedSecrets(t *testing.T) {
+	secrets := fakeSecrets{"short": "secret", "long": "another-secret"}
+	action := models.Action{
+		Method:  "GET",
+		URL:     "https://api.example.com/",
+		Headers: map[string]string{"A": `{{ secret "short" }}`, "B": `{{ secret "long" }}`},
+	}
+
+	rendered, err := RenderAction(action, testTemplateData(), secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rendered.Redact("got another-secret"); got != "got "+redactedValue {
+		t.Errorf("Redact() = %q, want the longer secret replaced whole", got)
+	}
+}
+
+func TestRenderActionRejectsTransformedSecrets(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{name: "bare", url: `https://api.example.com/?k={{ secret "token" }}`},
+		{name: "bare with trim markers", url: `https://api.example.com/?k={{- secret "token" -}}`},
+		{name: "bare inside if", url: `https://api.example.com/?k={{ if .Vars }}{{ secret "token" }}{{ end }}`},
+		{name: "piped", url: `https://api.example.com/?k={{ secret "token" | urlquery }}`, wantErr: true},
+		{name: "argument", url: `https://api.example.com/?k={{ printf "%x" (secret "token") }}`, wantErr: true},
+		{name: "sliced", url: `https://api.example.com/?k={{ slice (secret "token") 1 }}`, wantErr: true},
+		{name: "json escaped", url: `https://api.example.com/?k={{ jsonEscape (secret "token") }}`, wantErr: true},
+		{name: "assigned", url: `https://api.example.com/?k={{ $t := secret "token" }}{{ $t }}`, wantErr: true},
+		{name: "with", url: `https://api.example.com/?k={{ with secret "token" }}{{ printf "%x" . }}{{ end }}`, wantErr: true},
+		{name: "condition", url: `https://api.example.com/?k={{ if eq (secret "token") "x" }}y{{ end }}`, wantErr: true},
+		{name: "defined template", url: `{{ define "t" }}{{ secret "token" | printf "%x" }}{{ end }}https://api.example.com/?k={{ template "t" }}`, wantErr: true},
+		{name: "function value", url: `https://api.example.com/?k={{ call secret "token" }}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := models.Action{Method: "GET", URL: tt.url}
+			err := ValidateAction(action, fakeSecrets{"token": "abc"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := RenderAction(action, testTemplateData(), fakeSecrets{"token": "abc"}); (err != nil) != tt.wantErr {
+				t.Fatalf("RenderAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
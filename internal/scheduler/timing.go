@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+// requestTrace records connection and transfer timestamps for one request.
+type requestTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	remoteAddr   string
+	reused       bool
+}
+
+func newRequestTrace() *requestTrace {
+	return &requestTrace{}
+}
+
+// begin marks the moment the request is handed to the HTTP client.
+func (t *requestTrace) begin() {
+	t.set(&t.start)
+}
+
+func (t *requestTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Only the first dial attempt counts when several addresses are tried
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.set(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { t.set(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { t.set(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.reused = info.Reused
+			if info.Conn != nil {
+				if addr, ok := info.Conn.RemoteAddr().(*net.TCPAddr); ok {
+					t.remoteAddr = addr.IP.String()
+				}
+			}
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.set(&t.wroteRequest) },
+		GotFirstResponseByte: func() { t.set(&t.firstByte) },
+	}
+}
+
+func (t *requestTrace) set(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*field = time.Now()
+}
+
+// Timing summarises the trace once the response body has been read at done.
+func (t *requestTrace) Timing(done time.Time) *models.RequestTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timing := &models.RequestTiming{
+		DNSLookupMs:    elapsedMs(t.dnsStart, t.dnsDone),
+		TCPConnectMs:   elapsedMs(t.connectStart, t.connectDone),
+		TLSHandshakeMs: elapsedMs(t.tlsStart, t.tlsDone),
+		RemoteIP:       t.remoteAddr,
+		ConnReused:     t.reused,
+	}
+	if !t.firstByte.IsZero() {
+		timing.TimeToFirstByteMs = elapsedMs(t.start, t.firstByte)
+		timing.ContentTransferMs = elapsedMs(t.firstByte, done)
+		if !t.wroteRequest.IsZero() {
+			timing.ServerProcessingMs = elapsedMs(t.wroteRequest, t.firstByte)
+		}
+	}
+	return timing
+}
+
+func elapsedMs(from, to time.Time) float64 {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return float64(to.Sub(from).Microseconds()) / 1000
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// BuildTLSConfig turns a TLS profile into a tls.Config, loading the client
+// private key from the secrets store. Profiles that skip certificate
+// verification are rejected unless allowInsecure is set.
+func BuildTLSConfig(profile *models.TLSProfile, resolver SecretResolver, allowInsecure bool) (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName: profile.ServerName,
+	}
+
+	if profile.MinVersion != "" {
+		version, ok := tlsVersions[profile.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid min_version: %s", profile.MinVersion)
+		}
+		cfg.MinVersion = version
+	}
+
+	if profile.CABundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(profile.CABundle)) {
+			return nil, fmt.Errorf("ca_bundle contains no valid PEM certificates")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if profile.ClientCert != "" || profile.ClientKeySecret != "" {
+		if profile.ClientCert == "" || profile.ClientKeySecret == "" {
+			return nil, fmt.Errorf("client_cert and client_key_secret must be set together")
+		}
+		if resolver == nil {
+			return nil, fmt.Errorf("client key cannot be resolved: secrets store is not configured")
+		}
+		key, err := resolver.ResolveSecret(profile.ClientKeySecret)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair([]byte(profile.ClientCert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if profile.InsecureSkipVerify {
+		if !allowInsecure {
+			return nil, fmt.Errorf("insecure_skip_verify is disabled on this server (set EXECUTOR_ALLOW_INSECURE_TLS=true to allow it)")
+		}
+		cfg.InsecureSkipVerify = true
+	}
+
+	return cfg, nil
+}
+
+type pooledClient struct {
+	version    time.Time
+	keyVersion time.Time
+	client     *http.Client
+}
+
+// clientPool keeps one HTTP client per TLS profile so connections to targets
+// sharing a profile are reused. A client is rebuilt when its profile or the
+// secret holding its client key changes.
+type clientPool struct {
+	timeout       time.Duration
+	secrets       SecretResolver
+	allowInsecure bool
+	newTransport  func() *http.Transport
+	mu            sync.Mutex
+	clients       map[string]pooledClient
+}
+
+func newClientPool(timeout time.Duration, secrets SecretResolver, allowInsecure bool, newTransport func() *http.Transport) *clientPool {
+	return &clientPool{
+		timeout:       timeout,
+		secrets:       secrets,
+		allowInsecure: allowInsecure,
+		newTransport:  newTransport,
+		clients:       make(map[string]pooledClient),
+	}
+}
+
+// Client returns the pooled client for profile. keyUpdatedAt is when the
+// profile's client key secret was last changed, or zero if it has none.
+func (p *clientPool) Client(profile *models.TLSProfile, keyUpdatedAt time.Time) (*http.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pooled, ok := p.clients[profile.Name]
+	if ok && pooled.version.Equal(profile.UpdatedAt) && pooled.keyVersion.Equal(keyUpdatedAt) {
+		return pooled.client, nil
+	}
+
+	tlsConfig, err := BuildTLSConfig(profile, p.secrets, p.allowInsecure)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := p.newTransport()
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{
+		Timeout:   p.timeout,
+		Transport: transport,
+	}
+
+	if ok {
+		pooled.client.CloseIdleConnections()
+	}
+	p.clients[profile.Name] = pooledClient{version: profile.UpdatedAt, keyVersion: keyUpdatedAt, client: client}
+	return client, nil
+}
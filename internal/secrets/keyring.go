@@ -0,0 +1,102 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrNotConfigured = errors.New("secrets store is not configured: set SECRETS_MASTER_KEY")
+
+// Keyring encrypts values with the current master key and can decrypt values
+// sealed under any previous key, which allows keys to be rotated.
+type Keyring struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+// NewKeyring builds a keyring from base64-encoded 32-byte AES keys. An empty
+// current key yields a nil keyring, meaning secrets are disabled.
+func NewKeyring(current string, previous []string) (*Keyring, error) {
+	if current == "" {
+		return nil, nil
+	}
+
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+
+	id, err := k.addKey(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	k.currentID = id
+
+	for _, key := range previous {
+		if strings.TrimSpace(key) == "" {
+			continue
+		}
+		if _, err := k.addKey(key); err != nil {
+			return nil, fmt.Errorf("invalid previous master key: %w", err)
+		}
+	}
+
+	return k, nil
+}
+
+func (k *Keyring) addKey(encoded string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", err
+	}
+	if len(raw) != 32 {
+		return "", fmt.Errorf("key must be 32 bytes, got %d", len(raw))
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(raw)
+	id := hex.EncodeToString(sum[:4])
+	k.keys[id] = aead
+	return id, nil
+}
+
+// CurrentKeyID returns the identifier of the key used for new encryptions.
+func (k *Keyring) CurrentKeyID() string {
+	return k.currentID
+}
+
+// Encrypt seals plaintext with the current key. The name is bound as
+// additional data so a ciphertext cannot be moved to another secret.
+func (k *Keyring) Encrypt(name string, plaintext []byte) (ciphertext, nonce []byte, keyID string, err error) {
+	aead := k.keys[k.currentID]
+	nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, "", err
+	}
+	return aead.Seal(nil, nonce, plaintext, []byte(name)), nonce, k.currentID, nil
+}
+
+// Decrypt opens a ciphertext sealed under the key identified by keyID.
+func (k *Keyring) Decrypt(name string, ciphertext, nonce []byte, keyID string) ([]byte, error) {
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown encryption key %s", keyID)
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret %s: %w", name, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ayushsarode/task-scheduler/internal/logging"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+)
+
+// AdminService exposes operational controls over the running scheduler.
+type AdminService struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewAdminService(scheduler *scheduler.Scheduler) *AdminService {
+	return &AdminService{
+		scheduler: scheduler,
+	}
+}
+
+func (s *AdminService) ListCircuitBreakers(ctx context.Context) []models.CircuitBreakerState {
+	_, span := tracing.Start(ctx, "AdminService.ListCircuitBreakers")
+	defer span.End()
+
+	return s.scheduler.CircuitBreakers()
+}
+
+func (s *AdminService) ResetCircuitBreaker(ctx context.Context, host string) bool {
+	_, span := tracing.Start(ctx, "AdminService.ResetCircuitBreaker")
+	defer span.End()
+
+	return s.scheduler.ResetCircuitBreaker(host)
+}
+
+func (s *AdminService) GetLogLevels(ctx context.Context) models.LogLevels {
+	_, span := tracing.Start(ctx, "AdminService.GetLogLevels")
+	defer span.End()
+
+	return logging.Levels()
+}
+
+func (s *AdminService) SetLogLevel(ctx context.Context, req models.SetLogLevelRequest) (models.LogLevels, error) {
+	_, span := tracing.Start(ctx, "AdminService.SetLogLevel")
+	defer span.End()
+
+	if err := logging.SetLevel(req.Package, req.Level); err != nil {
+		return models.LogLevels{}, err
+	}
+	return logging.Levels(), nil
+}
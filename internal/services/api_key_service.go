@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/audit"
+	"github.com/ayushsarode/task-scheduler/internal/config"
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyPrefix       = "tsk_"
+	apiKeyPrefixLength = 12
+	// bootstrapKeyName attributes requests made with the bootstrap key
+	bootstrapKeyName = "bootstrap"
+)
+
+type APIKeyService struct {
+	repo *db.Repository
+	cfg  config.AuthConfig
+}
+
+func NewAPIKeyService(repo *db.Repository, cfg config.AuthConfig) *APIKeyService {
+	return &APIKeyService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Enabled reports whether API requests must be authenticated.
+func (s *APIKeyService) Enabled() bool {
+	return s.cfg.Enabled
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are long and random, so
+// a fast hash is enough to keep stored hashes from being reversed.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateKey generates a new key. Its value is only returned here.
+func (s *APIKeyService) CreateKey(ctx context.Context, req models.CreateAPIKeyRequest) (*models.CreatedAPIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.CreateKey")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if req.Name == bootstrapKeyName {
+		return nil, fmt.Errorf("api key name %q is reserved", bootstrapKeyName)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expires_at must be in the future")
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	value := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	now := time.Now()
+	key := models.APIKey{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Prefix:    value[:apiKeyPrefixLength],
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: now,
+		CreatedBy: audit.ActorFrom(ctx).Name,
+		UpdatedAt: now,
+	}
+
+	if err := repo.CreateAPIKey(&key, hashAPIKey(value)); err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPIKey{APIKey: key, Key: value}, nil
+}
+
+func (s *APIKeyService) GetKey(ctx context.Context, name string) (*models.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.GetKey")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.GetAPIKeyByName(name)
+}
+
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.ListKeys")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.ListAPIKeys()
+}
+
+func (s *APIKeyService) UpdateKey(ctx context.Context, name string, req models.UpdateAPIKeyRequest) (*models.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.UpdateKey")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	key, err := repo.GetAPIKeyByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Scopes != nil {
+		key.Scopes = *req.Scopes
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("expires_at must be in the future")
+		}
+		key.ExpiresAt = req.ExpiresAt
+	}
+	if req.ClearExpiresAt {
+		key.ExpiresAt = nil
+	}
+	key.UpdatedAt = time.Now()
+
+	if err := repo.UpdateAPIKey(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// DeleteKey revokes a key immediately.
+func (s *APIKeyService) DeleteKey(ctx context.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "APIKeyService.DeleteKey")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.DeleteAPIKey(name)
+}
+
+// Authenticate returns the key matching value. The bootstrap key, when
+// configured, authenticates as an admin key that is not stored.
+func (s *APIKeyService) Authenticate(ctx context.Context, value string) (*models.APIKey, error) {
+	ctx, span := tracing.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	if s.cfg.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(value), []byte(s.cfg.BootstrapKey)) == 1 {
+		return &models.APIKey{Name: bootstrapKeyName, Scopes: models.APIScopes{models.ScopeAdmin}}, nil
+	}
+
+	repo := s.repo.WithContext(ctx)
+	key, err := repo.GetAPIKeyByHash(hashAPIKey(value))
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return nil, fmt.Errorf("invalid api key")
+		}
+		return nil, err
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("api key expired")
+	}
+
+	return key, nil
+}
+
+// MarkUsed records that a key authenticated a request.
+func (s *APIKeyService) MarkUsed(ctx context.Context, key *models.APIKey) error {
+	if key.ID == uuid.Nil {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "APIKeyService.MarkUsed")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.TouchAPIKey(key.ID, time.Now())
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+)
+
+type AuditService struct {
+	repo *db.Repository
+}
+
+func NewAuditService(repo *db.Repository) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+func (s *AuditService) ListEvents(ctx context.Context, params models.ListAuditParams) ([]models.AuditEvent, int, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.ListEvents")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.ListAuditEvents(params)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+)
+
+type AuthProfileService struct {
+	repo *db.Repository
+}
+
+func NewAuthProfileService(repo *db.Repository) *AuthProfileService {
+	return &AuthProfileService{
+		repo: repo,
+	}
+}
+
+func (s *AuthProfileService) CreateAuthProfile(ctx context.Context, req models.CreateAuthProfileRequest) (*models.AuthProfile, error) {
+	ctx, span := tracing.Start(ctx, "AuthProfileService.CreateAuthProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if err := s.validateClientSecret(req.ClientSecretName); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	profile := &models.AuthProfile{
+		ID:               uuid.New(),
+		Name:             req.Name,
+		TokenURL:         req.TokenURL,
+		ClientID:         req.ClientID,
+		ClientSecretName: req.ClientSecretName,
+		Scopes:           req.Scopes,
+		Audience:         req.Audience,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if err := repo.CreateAuthProfile(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *AuthProfileService) GetAuthProfile(ctx context.Context, name string) (*models.AuthProfile, error) {
+	ctx, span := tracing.Start(ctx, "AuthProfileService.GetAuthProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.GetAuthProfileByName(name)
+}
+
+func (s *AuthProfileService) ListAuthProfiles(ctx context.Context) ([]models.AuthProfile, error) {
+	ctx, span := tracing.Start(ctx, "AuthProfileService.ListAuthProfiles")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.ListAuthProfiles()
+}
+
+func (s *AuthProfileService) UpdateAuthProfile(ctx context.Context, name string, req models.UpdateAuthProfileRequest) (*models.AuthProfile, error) {
+	ctx, span := tracing.Start(ctx, "AuthProfileService.UpdateAuthProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	profile, err := repo.GetAuthProfileByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TokenURL != nil {
+		profile.TokenURL = *req.TokenURL
+	}
+	if req.ClientID != nil {
+		profile.ClientID = *req.ClientID
+	}
+	if req.ClientSecretName != nil {
+		if err := s.validateClientSecret(*req.ClientSecretName); err != nil {
+			return nil, err
+		}
+		profile.ClientSecretName = *req.ClientSecretName
+	}
+	if req.Scopes != nil {
+		profile.Scopes = *req.Scopes
+	}
+	if req.Audience != nil {
+		profile.Audience = *req.Audience
+	}
+
+	// Bumping updated_at also invalidates tokens cached by the executor
+	profile.UpdatedAt = time.Now()
+
+	if err := repo.UpdateAuthProfile(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *AuthProfileService) DeleteAuthProfile(ctx context.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "AuthProfileService.DeleteAuthProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.DeleteAuthProfile(name)
+}
+
+func (s *AuthProfileService) validateClientSecret(name string) error {
+	if _, err := s.repo.GetSecretByName(name); err != nil {
+		return fmt.Errorf("client secret %q not found in secrets store", name)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/alerting"
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+)
+
+type ChannelService struct {
+	repo    *db.Repository
+	alerter *alerting.Alerter
+	egress  *scheduler.EgressPolicy
+}
+
+func NewChannelService(repo *db.Repository, alerter *alerting.Alerter, egress *scheduler.EgressPolicy) *ChannelService {
+	return &ChannelService{
+		repo:    repo,
+		alerter: alerter,
+		egress:  egress,
+	}
+}
+
+func (s *ChannelService) CreateChannel(ctx context.Context, req models.CreateChannelRequest) (*models.NotificationChannel, error) {
+	ctx, span := tracing.Start(ctx, "ChannelService.CreateChannel")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if err := s.validateConfig(req.Type, req.Config); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	channel := &models.NotificationChannel{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Type:      req.Type,
+		Config:    req.Config,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := repo.CreateChannel(channel); err != nil {
+		return nil, err
+	}
+
+	redacted := channel.Redacted()
+	return &redacted, nil
+}
+
+func (s *ChannelService) GetChannel(ctx context.Context, name string) (*models.NotificationChannel, error) {
+	ctx, span := tracing.Start(ctx, "ChannelService.GetChannel")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	channel, err := repo.GetChannelByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := channel.Redacted()
+	return &redacted, nil
+}
+
+func (s *ChannelService) ListChannels(ctx context.Context) ([]models.NotificationChannel, error) {
+	ctx, span := tracing.Start(ctx, "ChannelService.ListChannels")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	channels, err := repo.ListChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range channels {
+		channels[i] = channels[i].Redacted()
+	}
+	return channels, nil
+}
+
+func (s *ChannelService) UpdateChannel(ctx context.Context, name string, req models.UpdateChannelRequest) (*models.NotificationChannel, error) {
+	ctx, span := tracing.Start(ctx, "ChannelService.UpdateChannel")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	channel, err := repo.GetChannelByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Config != nil {
+		if err := s.validateConfig(channel.Type, *req.Config); err != nil {
+			return nil, err
+		}
+		// Headers read back from the API are redacted; keep their stored values
+		for name, value := range req.Config.Headers {
+			if stored, ok := channel.Config.Headers[name]; ok && value == models.RedactedHeaderValue {
+				req.Config.Headers[name] = stored
+			}
+		}
+		channel.Config = *req.Config
+	}
+	channel.UpdatedAt = time.Now()
+
+	if err := repo.UpdateChannel(channel); err != nil {
+		return nil, err
+	}
+
+	redacted := channel.Redacted()
+	return &redacted, nil
+}
+
+// DeleteChannel removes a channel unless an active task's alert rules still
+// reference it.
+func (s *ChannelService) DeleteChannel(ctx context.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "ChannelService.DeleteChannel")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	tasks, err := repo.ListTasksUsingChannel(name)
+	if err != nil {
+		return err
+	}
+	if len(tasks) > 0 {
+		return fmt.Errorf("channel is used by the alert rules of tasks: %s", strings.Join(tasks, ", "))
+	}
+
+	return repo.DeleteChannel(name)
+}
+
+// TestChannel sends a test notification through the channel and returns the
+// delivery attempts.
+func (s *ChannelService) TestChannel(ctx context.Context, name string) ([]models.AlertDelivery, error) {
+	ctx, span := tracing.Start(ctx, "ChannelService.TestChannel")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	channel, err := repo.GetChannelByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.alerter.SendTest(ctx, channel)
+}
+
+func (s *ChannelService) ListDeliveries(ctx context.Context, name string, params models.ListDeliveriesParams) ([]models.AlertDelivery, int, error) {
+	ctx, span := tracing.Start(ctx, "ChannelService.ListDeliveries")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if _, err := repo.GetChannelByName(name); err != nil {
+		return nil, 0, err
+	}
+
+	return repo.ListAlertDeliveries(name, params)
+}
+
+func (s *ChannelService) validateConfig(channelType models.ChannelType, cfg models.ChannelConfig) error {
+	switch channelType {
+	case models.ChannelWebhook, models.ChannelSlack:
+		if (cfg.URL == "") == (cfg.URLSecretName == "") {
+			return fmt.Errorf("%s channel requires exactly one of url or url_secret_name", channelType)
+		}
+		if cfg.URLSecretName != "" {
+			if _, err := s.repo.GetSecretByName(cfg.URLSecretName); err != nil {
+				return fmt.Errorf("url_secret_name: secret %q not found", cfg.URLSecretName)
+			}
+			return nil
+		}
+		parsed, err := url.Parse(cfg.URL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%s channel url must be an absolute http or https URL", channelType)
+		}
+		return s.egress.CheckHost(parsed.Hostname())
+
+	case models.ChannelEmail:
+		if cfg.SMTPHost == "" {
+			return fmt.Errorf("email channel requires smtp_host")
+		}
+		if cfg.SMTPPort < 0 || cfg.SMTPPort > 65535 {
+			return fmt.Errorf("email channel smtp_port is out of range")
+		}
+		if _, err := mail.ParseAddress(cfg.From); err != nil {
+			return fmt.Errorf("email channel from address is invalid: %w", err)
+		}
+		if len(cfg.To) == 0 {
+			return fmt.Errorf("email channel requires at least one to address")
+		}
+		for _, to := range cfg.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				return fmt.Errorf("email channel to address %q is invalid: %w", to, err)
+			}
+		}
+		if cfg.Username != "" {
+			if cfg.PasswordSecretName == "" {
+				return fmt.Errorf("email channel requires password_secret_name with username")
+			}
+			if _, err := s.repo.GetSecretByName(cfg.PasswordSecretName); err != nil {
+				return fmt.Errorf("password_secret_name: secret %q not found", cfg.PasswordSecretName)
+			}
+		}
+		return s.egress.CheckHost(cfg.SMTPHost)
+
+	default:
+		return fmt.Errorf("invalid channel type: %s", channelType)
+	}
+}
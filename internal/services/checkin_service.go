@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/metrics"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+)
+
+type CheckinService struct {
+	repo     *db.Repository
+	observer scheduler.ResultObserver
+}
+
+func NewCheckinService(repo *db.Repository, observer scheduler.ResultObserver) *CheckinService {
+	return &CheckinService{
+		repo:     repo,
+		observer: observer,
+	}
+}
+
+// newCheckinToken returns a random, URL-safe ping token.
+func newCheckinToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate check-in token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Ping records a ping on a check-in monitor. A start ping marks a run as
+// started; success and fail pings are stored as results, timed from the
+// start ping when there was one, and evaluated against the task's alert rules.
+func (s *CheckinService) Ping(ctx context.Context, token string, ping models.Ping) (*models.Checkin, error) {
+	ctx, span := tracing.Start(ctx, "CheckinService.Ping")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	task, err := repo.GetTaskByCheckinToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status == models.StatusCancelled {
+		return nil, fmt.Errorf("check-in not found")
+	}
+
+	now := time.Now()
+	checkin := task.Checkin
+	if ping.Kind == models.PingStart {
+		if err := repo.StartCheckin(task.ID, now); err != nil {
+			return nil, err
+		}
+		checkin.StartedAt = &now
+		checkin.LastPingAt = &now
+		return checkin, nil
+	}
+
+	result := &models.TaskResult{
+		ID:          uuid.New(),
+		TaskID:      task.ID,
+		TaskVersion: task.Version,
+		RunAt:       now,
+		Success:     ping.Kind == models.PingSuccess,
+		CreatedAt:   now,
+		TraceID:     tracing.TraceID(ctx),
+	}
+	if checkin.StartedAt != nil {
+		result.RunAt = *checkin.StartedAt
+		result.DurationMs = now.Sub(*checkin.StartedAt).Milliseconds()
+	}
+	if requestJSON, err := json.Marshal(ping); err == nil {
+		result.Request = json.RawMessage(requestJSON)
+	}
+	result.ResponseBody = ping.Body
+	result.BodySize = ping.BodySize
+	result.BodyTruncated = ping.Truncated
+
+	status := models.CheckinUp
+	outcome := "success"
+	if !result.Success {
+		status = models.CheckinDown
+		outcome = string(models.ErrorTypeCheckin)
+		errorMsg := "Check-in reported failure"
+		result.ErrorMessage = &errorMsg
+		result.ErrorType = models.ErrorTypeCheckin
+	}
+	metrics.ObserveExecution(task.ID.String(), outcome, 0, time.Duration(result.DurationMs)*time.Millisecond)
+
+	counters, err := repo.CreateTaskResult(result, task.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.FinishCheckin(task.ID, status, now); err != nil {
+		return nil, err
+	}
+
+	// Alerts are delivered in the background so the pinging job isn't held up
+	if s.observer != nil {
+		go s.observer.Observe(context.WithoutCancel(ctx), task, result, counters)
+	}
+
+	checkin.Status = status
+	checkin.StartedAt = nil
+	checkin.LastPingAt = &now
+	return checkin, nil
+}
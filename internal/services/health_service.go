@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/version"
+)
+
+// HealthService answers liveness and readiness probes.
+type HealthService struct {
+	repo           *db.Repository
+	scheduler      *scheduler.Scheduler
+	migrationsPath string
+	timeout        time.Duration
+	startedAt      time.Time
+}
+
+func NewHealthService(repo *db.Repository, scheduler *scheduler.Scheduler, migrationsPath string, timeout time.Duration) *HealthService {
+	return &HealthService{
+		repo:           repo,
+		scheduler:      scheduler,
+		migrationsPath: migrationsPath,
+		timeout:        timeout,
+		startedAt:      time.Now(),
+	}
+}
+
+// Live reports that the process is up and serving. It checks no dependencies.
+func (s *HealthService) Live() models.HealthReport {
+	return s.report(models.CheckOK, []models.HealthCheck{})
+}
+
+// Ready checks the database, the migration status and the scheduler. The
+// report fails if any check fails.
+func (s *HealthService) Ready(ctx context.Context) models.HealthReport {
+	checks := []models.HealthCheck{
+		s.check(ctx, "database", func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, s.repo.WithContext(ctx).Ping()
+		}),
+		s.check(ctx, "migrations", func(ctx context.Context) (map[string]interface{}, error) {
+			pending, err := s.repo.WithContext(ctx).PendingMigrations(s.migrationsPath)
+			if err != nil {
+				return nil, err
+			}
+			if len(pending) > 0 {
+				return map[string]interface{}{"pending": pending}, fmt.Errorf("%d migrations pending", len(pending))
+			}
+			return nil, nil
+		}),
+		s.check(ctx, "scheduler", func(context.Context) (map[string]interface{}, error) {
+			return s.scheduler.Liveness()
+		}),
+	}
+
+	status := models.CheckOK
+	for _, check := range checks {
+		if check.Status != models.CheckOK {
+			status = models.CheckFailed
+		}
+	}
+	return s.report(status, checks)
+}
+
+// check runs fn under the check timeout and records its outcome and latency.
+func (s *HealthService) check(ctx context.Context, name string, fn func(context.Context) (map[string]interface{}, error)) models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := fn(ctx)
+	check := models.HealthCheck{
+		Name:      name,
+		Status:    models.CheckOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+	if err != nil {
+		check.Status = models.CheckFailed
+		check.Error = err.Error()
+	}
+	return check
+}
+
+func (s *HealthService) report(status models.CheckStatus, checks []models.HealthCheck) models.HealthReport {
+	return models.HealthReport{
+		Status:  status,
+		Checks:  checks,
+		Version: version.Get(),
+		Uptime:  time.Since(s.startedAt).Round(time.Second).String(),
+		Time:    time.Now().UTC(),
+	}
+}
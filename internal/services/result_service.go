@@ -1,25 +1,92 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/ayushsarode/task-scheduler/internal/blobstore"
 	"github.com/ayushsarode/task-scheduler/internal/db"
 	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
 )
 
 type ResultService struct {
-	repo *db.Repository
+	repo  *db.Repository
+	blobs *blobstore.FSStore
 }
 
-func NewResultService(repo *db.Repository) *ResultService {
+func NewResultService(repo *db.Repository, blobs *blobstore.FSStore) *ResultService {
 	return &ResultService{
-		repo: repo,
+		repo:  repo,
+		blobs: blobs,
 	}
 }
 
-func (s *ResultService) GetTaskResults(taskID uuid.UUID, page, limit int) ([]models.TaskResult, int, error) {
-	return s.repo.GetTaskResults(taskID, page, limit)
+func (s *ResultService) GetTaskResults(ctx context.Context, taskID uuid.UUID, page, limit int) ([]models.TaskResult, int, error) {
+	ctx, span := tracing.Start(ctx, "ResultService.GetTaskResults")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.GetTaskResults(taskID, page, limit)
+}
+
+func (s *ResultService) ListAllResults(ctx context.Context, params models.ListResultsParams) ([]models.TaskResult, int, error) {
+	ctx, span := tracing.Start(ctx, "ResultService.ListAllResults")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.ListAllResults(params)
 }
 
-func (s *ResultService) ListAllResults(params models.ListResultsParams) ([]models.TaskResult, int, error) {
-	return s.repo.ListAllResults(params)
-}
\ No newline at end of file
+// OpenResultBody returns the full response body of a result and its content
+// type. Offloaded bodies are streamed from the blob store; otherwise the
+// stored (possibly truncated) body is returned. runAt, if known, narrows the
+// lookup to one partition.
+func (s *ResultService) OpenResultBody(ctx context.Context, id uuid.UUID, runAt *time.Time) (io.ReadCloser, string, error) {
+	ctx, span := tracing.Start(ctx, "ResultService.OpenResultBody")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	result, err := repo.GetTaskResultByID(id, runAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := "application/octet-stream"
+	var headers models.ResponseHeaders
+	if err := json.Unmarshal(result.ResponseHeaders, &headers); err == nil {
+		if values := headers["Content-Type"]; len(values) > 0 {
+			contentType = values[0]
+		}
+	}
+
+	if result.BodyBlobKey != "" && s.blobs != nil {
+		body, err := s.blobs.Open(result.BodyBlobKey)
+		if err == nil {
+			return body, contentType, nil
+		}
+		if err != blobstore.ErrNotFound {
+			return nil, "", err
+		}
+	}
+
+	return io.NopCloser(strings.NewReader(result.ResponseBody)), contentType, nil
+}
+
+// GetStats aggregates results for one task, or for all tasks when taskID is nil.
+func (s *ResultService) GetStats(ctx context.Context, taskID *uuid.UUID, from, to time.Time, interval time.Duration) (*models.ExecutionStats, error) {
+	ctx, span := tracing.Start(ctx, "ResultService.GetStats")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if taskID != nil {
+		if _, err := repo.GetTaskByID(*taskID); err != nil {
+			return nil, err
+		}
+	}
+	return repo.GetExecutionStats(taskID, from, to, interval)
+}
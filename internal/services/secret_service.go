@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/secrets"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+)
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+type SecretService struct {
+	repo    *db.Repository
+	keyring *secrets.Keyring
+}
+
+func NewSecretService(repo *db.Repository, keyring *secrets.Keyring) *SecretService {
+	return &SecretService{
+		repo:    repo,
+		keyring: keyring,
+	}
+}
+
+func (s *SecretService) CreateSecret(ctx context.Context, req models.CreateSecretRequest) (*models.Secret, error) {
+	ctx, span := tracing.Start(ctx, "SecretService.CreateSecret")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if s.keyring == nil {
+		return nil, secrets.ErrNotConfigured
+	}
+	if !secretNamePattern.MatchString(req.Name) {
+		return nil, fmt.Errorf("secret name may only contain letters, digits, '_', '.' and '-'")
+	}
+
+	ciphertext, nonce, keyID, err := s.keyring.Encrypt(req.Name, []byte(req.Value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	now := time.Now()
+	secret := &models.Secret{
+		ID:         uuid.New(),
+		Name:       req.Name,
+		KeyID:      keyID,
+		Ciphertext: ciphertext,
+		Nonce:      nonce,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := repo.CreateSecret(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (s *SecretService) ListSecrets(ctx context.Context) ([]models.Secret, error) {
+	ctx, span := tracing.Start(ctx, "SecretService.ListSecrets")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.ListSecrets()
+}
+
+func (s *SecretService) UpdateSecret(ctx context.Context, name string, req models.UpdateSecretRequest) (*models.Secret, error) {
+	ctx, span := tracing.Start(ctx, "SecretService.UpdateSecret")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if s.keyring == nil {
+		return nil, secrets.ErrNotConfigured
+	}
+
+	secret, err := repo.GetSecretByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.seal(secret, []byte(req.Value)); err != nil {
+		return nil, err
+	}
+
+	if err := repo.UpdateSecret(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (s *SecretService) DeleteSecret(ctx context.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "SecretService.DeleteSecret")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.DeleteSecret(name)
+}
+
+// RotateKeys re-encrypts every secret that is not sealed under the current
+// master key and returns how many were rotated.
+func (s *SecretService) RotateKeys(ctx context.Context) (int, error) {
+	ctx, span := tracing.Start(ctx, "SecretService.RotateKeys")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if s.keyring == nil {
+		return 0, secrets.ErrNotConfigured
+	}
+
+	all, err := repo.ListSecrets()
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for i := range all {
+		secret := &all[i]
+		if secret.KeyID == s.keyring.CurrentKeyID() {
+			continue
+		}
+
+		plaintext, err := s.keyring.Decrypt(secret.Name, secret.Ciphertext, secret.Nonce, secret.KeyID)
+		if err != nil {
+			return rotated, err
+		}
+		if err := s.seal(secret, plaintext); err != nil {
+			return rotated, err
+		}
+		if err := repo.UpdateSecret(secret); err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+
+	return rotated, nil
+}
+
+// ResolveSecret returns the plaintext value of a secret. It is used when a
+// secret is needed to make a request or build a TLS config; validating task
+// actions only checks that the secret exists.
+func (s *SecretService) ResolveSecret(name string) (string, error) {
+	if s.keyring == nil {
+		return "", secrets.ErrNotConfigured
+	}
+
+	secret, err := s.repo.GetSecretByName(name)
+	if err != nil {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+
+	plaintext, err := s.keyring.Decrypt(secret.Name, secret.Ciphertext, secret.Nonce, secret.KeyID)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+func (s *SecretService) seal(secret *models.Secret, plaintext []byte) error {
+	ciphertext, nonce, keyID, err := s.keyring.Encrypt(secret.Name, plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	secret.Ciphertext = ciphertext
+	secret.Nonce = nonce
+	secret.KeyID = keyID
+	secret.UpdatedAt = time.Now()
+	return nil
+}
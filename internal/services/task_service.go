@@ -1,16 +1,27 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/ayushsarode/task-scheduler/internal/audit"
 	"github.com/ayushsarode/task-scheduler/internal/db"
 	"github.com/ayushsarode/task-scheduler/internal/models"
 	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 )
 
+// ErrSecretAccessDenied is returned when an actor without the admin scope sets
+// an action that reads the secrets store. Secrets are managed with the admin
+// scope, and an action can send a secret to any host, so using one needs the
+// same scope.
+var ErrSecretAccessDenied = errors.New("actions that use secrets, signing, auth profiles or TLS profiles require the admin scope")
+
 type TaskService struct {
 	repo      *db.Repository
 	scheduler *scheduler.Scheduler
@@ -27,21 +38,68 @@ func (s *TaskService) GetRepository() *db.Repository {
 	return s.repo
 }
 
-func (s *TaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, error) {
-	// Validate trigger
-	if err := s.validateTrigger(req.Trigger); err != nil {
+func (s *TaskService) CreateTask(ctx context.Context, req models.CreateTaskRequest) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.CreateTask")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if req.Type == "" {
+		req.Type = models.TaskTypeHTTP
+	}
+
+	if req.Type == models.TaskTypeCheckin {
+		if req.Action.Method != "" || req.Action.URL != "" {
+			return nil, fmt.Errorf("check-in tasks have no action")
+		}
+		if err := s.validateCheckinSchedule(req.Trigger, req.Heartbeat); err != nil {
+			return nil, err
+		}
+	} else {
+		// Validate trigger
+		if err := s.validateTrigger(req.Trigger); err != nil {
+			return nil, err
+		}
+
+		// Validate action templates
+		if err := s.validateAction(ctx, req.Action); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateStoragePolicy(req.Storage); err != nil {
+		return nil, err
+	}
+
+	if err := validateRetentionPolicy(req.Retention); err != nil {
+		return nil, err
+	}
+
+	if err := validateHeartbeatPolicy(req.Heartbeat); err != nil {
+		return nil, err
+	}
+
+	if err := validateAlertRules(repo, req.Alerts, req.Heartbeat); err != nil {
 		return nil, err
 	}
 
 	now := time.Now()
+	actor := audit.ActorFrom(ctx).Name
 	task := &models.Task{
 		ID:        uuid.New(),
 		Name:      req.Name,
+		Type:      req.Type,
 		Trigger:   req.Trigger,
 		Action:    req.Action,
+		Storage:   req.Storage,
+		Retention: req.Retention,
+		Alerts:    req.Alerts,
+		Heartbeat: req.Heartbeat,
 		Status:    models.StatusScheduled,
+		Version:   1,
 		CreatedAt: now,
+		CreatedBy: actor,
 		UpdatedAt: now,
+		UpdatedBy: actor,
 	}
 
 	// Set next run time
@@ -49,8 +107,20 @@ func (s *TaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, er
 		task.NextRun = task.Trigger.DateTime
 	}
 
+	if task.Type == models.TaskTypeCheckin {
+		token, err := newCheckinToken()
+		if err != nil {
+			return nil, err
+		}
+		task.Checkin = &models.Checkin{Token: token, Status: models.CheckinNew}
+	}
+
 	// Save to database
-	if err := s.repo.CreateTask(task); err != nil {
+	event, err := audit.NewEvent(ctx, models.AuditCreate, nil, task)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.CreateTask(task, newTaskVersion(ctx, task, nil), event); err != nil {
 		return nil, err
 	}
 
@@ -62,20 +132,44 @@ func (s *TaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, er
 	return task, nil
 }
 
-func (s *TaskService) GetTask(id uuid.UUID) (*models.Task, error) {
-	return s.repo.GetTaskByID(id)
+func (s *TaskService) GetTask(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetTask")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	task, err := repo.GetTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	counters, err := repo.GetTaskRunCounters(id)
+	if err != nil {
+		return nil, err
+	}
+	task.Counters = counters
+
+	return task, nil
 }
 
-func (s *TaskService) ListTasks(params models.ListTasksParams) ([]models.Task, int, error) {
-	return s.repo.ListTasks(params)
+func (s *TaskService) ListTasks(ctx context.Context, params models.ListTasksParams) ([]models.Task, int, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.ListTasks")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.ListTasks(params)
 }
 
-func (s *TaskService) UpdateTask(id uuid.UUID, req models.UpdateTaskRequest) (*models.Task, error) {
+func (s *TaskService) UpdateTask(ctx context.Context, id uuid.UUID, req models.UpdateTaskRequest) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.UpdateTask")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
 	// Get existing task
-	task, err := s.repo.GetTaskByID(id)
+	task, err := repo.GetTaskByID(id)
 	if err != nil {
 		return nil, err
 	}
+	before := *task
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -83,8 +177,11 @@ func (s *TaskService) UpdateTask(id uuid.UUID, req models.UpdateTaskRequest) (*m
 	}
 
 	if req.Trigger != nil {
-		if err := s.validateTrigger(*req.Trigger); err != nil {
-			return nil, err
+		// Check-in schedules are validated below, with the heartbeat policy
+		if task.Type != models.TaskTypeCheckin {
+			if err := s.validateTrigger(*req.Trigger); err != nil {
+				return nil, err
+			}
 		}
 		task.Trigger = *req.Trigger
 
@@ -95,6 +192,12 @@ func (s *TaskService) UpdateTask(id uuid.UUID, req models.UpdateTaskRequest) (*m
 	}
 
 	if req.Action != nil {
+		if task.Type == models.TaskTypeCheckin {
+			return nil, fmt.Errorf("check-in tasks have no action")
+		}
+		if err := s.validateAction(ctx, *req.Action); err != nil {
+			return nil, err
+		}
 		task.Action = *req.Action
 	}
 
@@ -102,12 +205,72 @@ func (s *TaskService) UpdateTask(id uuid.UUID, req models.UpdateTaskRequest) (*m
 		task.Status = *req.Status
 	}
 
+	if req.Storage != nil {
+		if err := validateStoragePolicy(req.Storage); err != nil {
+			return nil, err
+		}
+		task.Storage = req.Storage
+	}
+
+	if req.Retention != nil {
+		if err := validateRetentionPolicy(req.Retention); err != nil {
+			return nil, err
+		}
+		task.Retention = req.Retention
+	}
+
+	if req.Heartbeat != nil {
+		if err := validateHeartbeatPolicy(req.Heartbeat); err != nil {
+			return nil, err
+		}
+		task.Heartbeat = req.Heartbeat
+	}
+	if req.ClearHeartbeat {
+		task.Heartbeat = nil
+	}
+	heartbeatChanged := req.Heartbeat != nil || req.ClearHeartbeat
+
+	if req.Alerts != nil {
+		task.Alerts = *req.Alerts
+	}
+	if req.Alerts != nil || heartbeatChanged {
+		if err := validateAlertRules(repo, task.Alerts, task.Heartbeat); err != nil {
+			return nil, err
+		}
+	}
+
+	if task.Type == models.TaskTypeCheckin && (req.Trigger != nil || heartbeatChanged) {
+		if err := s.validateCheckinSchedule(task.Trigger, task.Heartbeat); err != nil {
+			return nil, err
+		}
+	}
+
 	task.UpdatedAt = time.Now()
+	task.UpdatedBy = audit.ActorFrom(ctx).Name
 
-	// Save to database
-	if err := s.repo.UpdateTask(task); err != nil {
+	// Save to database, as a new version when the trigger or action changed
+	var version *models.TaskVersion
+	if configChanged(&before, task) {
+		task.Version++
+		version = newTaskVersion(ctx, task, nil)
+	}
+	event, err := audit.NewEvent(ctx, updateAction(before.Status, task.Status), &before, task)
+	if err != nil {
 		return nil, err
 	}
+	if err := repo.UpdateTaskWithAudit(task, before.Revision, version, event); err != nil {
+		return nil, err
+	}
+
+	// The watchdog no longer checks a task without a heartbeat policy, so it
+	// would never clear the flag itself
+	if task.Heartbeat == nil && before.Overdue {
+		if err := repo.ClearTaskOverdue(task.ID); err != nil {
+			return nil, err
+		}
+		task.Overdue = false
+		task.OverdueSince = nil
+	}
 
 	// Reschedule the task if still scheduled
 	if task.Status == models.StatusScheduled {
@@ -121,12 +284,197 @@ func (s *TaskService) UpdateTask(id uuid.UUID, req models.UpdateTaskRequest) (*m
 	return task, nil
 }
 
-func (s *TaskService) DeleteTask(id uuid.UUID) error {
+func (s *TaskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "TaskService.DeleteTask")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	task, err := repo.GetTaskByID(id)
+	if err != nil {
+		return err
+	}
+
 	// Remove from scheduler
 	s.scheduler.RemoveTask(id)
 
 	// Mark as cancelled in database
-	return s.repo.DeleteTask(id)
+	actor := audit.ActorFrom(ctx).Name
+	cancelled := *task
+	cancelled.Status = models.StatusCancelled
+	cancelled.UpdatedBy = actor
+	event, err := audit.NewEvent(ctx, models.AuditCancel, task, &cancelled)
+	if err != nil {
+		return err
+	}
+	if err := repo.DeleteTask(id, actor, event); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListVersions returns a task's versions, newest first, each with its
+// changes from the version before it.
+func (s *TaskService) ListVersions(ctx context.Context, id uuid.UUID, params models.ListVersionsParams) ([]models.TaskVersion, int, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.ListVersions")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	if _, err := repo.GetTaskByID(id); err != nil {
+		return nil, 0, err
+	}
+
+	versions, total, err := repo.ListTaskVersions(id, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range versions {
+		var previous *models.TaskVersion
+		if i+1 < len(versions) {
+			previous = &versions[i+1]
+		} else if versions[i].Version > 1 {
+			// The version before the last one on the page is on the next page
+			previous, err = repo.GetTaskVersion(id, versions[i].Version-1)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+		changes, err := versionChanges(previous, &versions[i])
+		if err != nil {
+			return nil, 0, err
+		}
+		versions[i].Changes = changes
+	}
+
+	return versions, total, nil
+}
+
+// RestoreVersion rolls a task's trigger and action back to an earlier
+// version. The restored configuration is saved as a new version and the task
+// is rescheduled if it is scheduled.
+func (s *TaskService) RestoreVersion(ctx context.Context, id uuid.UUID, number int) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.RestoreVersion")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	task, err := repo.GetTaskByID(id)
+	if err != nil {
+		return nil, err
+	}
+	before := *task
+
+	version, err := repo.GetTaskVersion(id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Type == models.TaskTypeCheckin {
+		if err := s.validateCheckinSchedule(version.Trigger, task.Heartbeat); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := s.validateTrigger(version.Trigger); err != nil {
+			return nil, err
+		}
+		if err := s.validateAction(ctx, version.Action); err != nil {
+			return nil, err
+		}
+	}
+
+	task.Trigger = version.Trigger
+	task.Action = version.Action
+	if !configChanged(&before, task) {
+		return task, nil
+	}
+
+	// Update next run time
+	if task.Trigger.Type == models.TriggerOneOff && task.Trigger.DateTime != nil {
+		task.NextRun = task.Trigger.DateTime
+	}
+
+	task.Version++
+	task.UpdatedAt = time.Now()
+	task.UpdatedBy = audit.ActorFrom(ctx).Name
+	event, err := audit.NewEvent(ctx, models.AuditRestore, &before, task)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.UpdateTaskWithAudit(task, before.Revision, newTaskVersion(ctx, task, &number), event); err != nil {
+		return nil, err
+	}
+
+	if task.Status == models.StatusScheduled {
+		if err := s.scheduler.ScheduleTask(task); err != nil {
+			return nil, fmt.Errorf("failed to reschedule task: %w", err)
+		}
+	}
+
+	return task, nil
+}
+
+// newTaskVersion snapshots a task's trigger and action as its current
+// version, attributed to the actor carried by ctx.
+func newTaskVersion(ctx context.Context, task *models.Task, restoredFrom *int) *models.TaskVersion {
+	return &models.TaskVersion{
+		TaskID:       task.ID,
+		Version:      task.Version,
+		Trigger:      task.Trigger,
+		Action:       task.Action,
+		CreatedAt:    task.UpdatedAt,
+		CreatedBy:    audit.ActorFrom(ctx).Name,
+		RestoredFrom: restoredFrom,
+	}
+}
+
+// configChanged reports whether a task's trigger or action differs from
+// before, comparing their stored JSON form.
+func configChanged(before, after *models.Task) bool {
+	changes, err := versionChanges(
+		&models.TaskVersion{Trigger: before.Trigger, Action: before.Action},
+		&models.TaskVersion{Trigger: after.Trigger, Action: after.Action},
+	)
+	return err != nil || len(changes) > 0
+}
+
+// versionChanges diffs the trigger and action of two versions field by
+// field, naming fields like trigger.cron and action.url.
+func versionChanges(previous, current *models.TaskVersion) (models.AuditChanges, error) {
+	var previousTrigger, previousAction interface{}
+	if previous != nil {
+		previousTrigger, previousAction = previous.Trigger, previous.Action
+	}
+
+	changes := models.AuditChanges{}
+	for prefix, pair := range map[string][2]interface{}{
+		"trigger": {previousTrigger, current.Trigger},
+		"action":  {previousAction, current.Action},
+	} {
+		diff, err := audit.Diff(pair[0], pair[1])
+		if err != nil {
+			return nil, err
+		}
+		for name, change := range diff {
+			changes[prefix+"."+name] = change
+		}
+	}
+	return changes, nil
+}
+
+// updateAction names an update in the audit log by its status transition.
+func updateAction(from, to models.TaskStatus) models.AuditAction {
+	switch {
+	case from == to:
+		return models.AuditUpdate
+	case to == models.StatusPaused:
+		return models.AuditPause
+	case to == models.StatusCancelled:
+		return models.AuditCancel
+	case from == models.StatusPaused && to == models.StatusScheduled:
+		return models.AuditResume
+	default:
+		return models.AuditUpdate
+	}
 }
 
 func (s *TaskService) validateTrigger(trigger models.Trigger) error {
@@ -155,3 +503,130 @@ func (s *TaskService) validateTrigger(trigger models.Trigger) error {
 	}
 	return nil
 }
+
+// validateCheckinSchedule checks that a check-in task expects pings either
+// on a cron schedule or at a heartbeat interval.
+func (s *TaskService) validateCheckinSchedule(trigger models.Trigger, heartbeat *models.HeartbeatPolicy) error {
+	if heartbeat == nil {
+		return fmt.Errorf("check-in tasks require a heartbeat policy")
+	}
+	switch trigger.Type {
+	case "":
+		if heartbeat.IntervalSeconds <= 0 {
+			return fmt.Errorf("check-in tasks require a cron trigger or a heartbeat interval_seconds")
+		}
+		return nil
+	case models.TriggerCron:
+		return s.validateTrigger(trigger)
+	default:
+		return fmt.Errorf("check-in tasks only take a cron trigger")
+	}
+}
+
+func (s *TaskService) validateAction(ctx context.Context, action models.Action) error {
+	if action.Method == "" {
+		return fmt.Errorf("invalid action: method is required")
+	}
+	if err := checkSecretAccess(ctx, action); err != nil {
+		return err
+	}
+	if err := s.scheduler.ValidateAction(action); err != nil {
+		return fmt.Errorf("invalid action: %w", err)
+	}
+	return nil
+}
+
+// checkSecretAccess rejects actions that use the secrets store unless the
+// actor has the admin scope.
+func checkSecretAccess(ctx context.Context, action models.Action) error {
+	if scheduler.UsesSecrets(action) && !audit.ActorFrom(ctx).Allows(models.ScopeAdmin) {
+		return ErrSecretAccessDenied
+	}
+	return nil
+}
+
+func validateStoragePolicy(policy *models.StoragePolicy) error {
+	if policy == nil {
+		return nil
+	}
+	switch policy.Mode {
+	case "", models.StorageAll, models.StorageFailuresOnly, models.StorageMetadataOnly, models.StorageNone:
+	default:
+		return fmt.Errorf("invalid storage mode: %s", policy.Mode)
+	}
+	if policy.MaxBodyBytes < 0 {
+		return fmt.Errorf("storage max_body_bytes must not be negative")
+	}
+	for _, header := range policy.Headers {
+		if strings.TrimSpace(header) == "" {
+			return fmt.Errorf("storage headers must not be empty")
+		}
+	}
+	return nil
+}
+
+func validateRetentionPolicy(policy *models.RetentionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAgeDays != nil && *policy.MaxAgeDays < 0 {
+		return fmt.Errorf("retention max_age_days must not be negative")
+	}
+	if policy.MaxRows != nil && *policy.MaxRows < 0 {
+		return fmt.Errorf("retention max_rows must not be negative")
+	}
+	return nil
+}
+
+func validateHeartbeatPolicy(policy *models.HeartbeatPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.IntervalSeconds < 0 {
+		return fmt.Errorf("heartbeat interval_seconds must not be negative")
+	}
+	if policy.GraceSeconds < 0 {
+		return fmt.Errorf("heartbeat grace_seconds must not be negative")
+	}
+	return nil
+}
+
+func validateAlertRules(repo *db.Repository, rules models.AlertRules, heartbeat *models.HeartbeatPolicy) error {
+	seen := map[models.AlertType]bool{}
+	for _, rule := range rules {
+		switch rule.Type {
+		case models.AlertConsecutiveFailures:
+			if rule.Failures < 1 {
+				return fmt.Errorf("alert rule %s requires failures of at least 1", rule.Type)
+			}
+		case models.AlertLatency:
+			if rule.LatencyMs <= 0 {
+				return fmt.Errorf("alert rule %s requires a positive latency_ms", rule.Type)
+			}
+		case models.AlertOverdue:
+			if heartbeat == nil {
+				return fmt.Errorf("alert rule %s requires a heartbeat policy", rule.Type)
+			}
+		case models.AlertRecovery:
+		default:
+			return fmt.Errorf("invalid alert rule type: %s", rule.Type)
+		}
+		if seen[rule.Type] {
+			return fmt.Errorf("duplicate alert rule type: %s", rule.Type)
+		}
+		seen[rule.Type] = true
+
+		if rule.RepeatAfterMinutes < 0 {
+			return fmt.Errorf("alert rule repeat_after_minutes must not be negative")
+		}
+		if len(rule.Channels) == 0 {
+			return fmt.Errorf("alert rule %s requires at least one channel", rule.Type)
+		}
+		for _, name := range rule.Channels {
+			if _, err := repo.GetChannelByName(name); err != nil {
+				return fmt.Errorf("notification channel %q not found", name)
+			}
+		}
+	}
+	return nil
+}
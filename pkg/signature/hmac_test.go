@@ -0,0 +1,142 @@
+package signature
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signedRequest signs an outgoing request with sign and returns it as the
+// receiving server would see it.
+func signedRequest(t *testing.T, method, target, body string, sign func(req *http.Request, body []byte)) *http.Request {
+	t.Helper()
+	out, err := http.NewRequest(method, "https://api.example.com"+target, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign(out, []byte(body))
+
+	in := httptest.NewRequest(method, target, strings.NewReader(body))
+	in.Host = "api.example.com"
+	in.Header = out.Header.Clone()
+	return in
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	secret := []byte("shared-secret")
+
+	tests := []struct {
+		name    string
+		signAt  time.Time
+		secret  []byte
+		mutate  func(r *http.Request)
+		wantErr error
+	}{
+		{name: "valid"},
+		{name: "wrong secret", secret: []byte("other-secret"), wantErr: ErrInvalidSignature},
+		{name: "tampered body", mutate: func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"amount":1000}`))
+		}, wantErr: ErrInvalidSignature},
+		{name: "tampered path", mutate: func(r *http.Request) { r.URL.Path = "/admin" }, wantErr: ErrInvalidSignature},
+		{name: "tampered query", mutate: func(r *http.Request) { r.URL.RawQuery = "id=2" }, wantErr: ErrInvalidSignature},
+		{name: "tampered method", mutate: func(r *http.Request) { r.Method = http.MethodDelete }, wantErr: ErrInvalidSignature},
+		{name: "tampered timestamp", mutate: func(r *http.Request) {
+			r.Header.Set(HeaderTimestamp, "1")
+		}, wantErr: ErrExpired},
+		{name: "expired", signAt: time.Now().Add(-10 * time.Minute), wantErr: ErrExpired},
+		{name: "from the future", signAt: time.Now().Add(10 * time.Minute), wantErr: ErrExpired},
+		{name: "missing signature", mutate: func(r *http.Request) { r.Header.Del(HeaderSignature) }, wantErr: ErrMissingSignature},
+		{name: "missing timestamp", mutate: func(r *http.Request) { r.Header.Del(HeaderTimestamp) }, wantErr: ErrMissingSignature},
+		{name: "signature not hex", mutate: func(r *http.Request) {
+			r.Header.Set(HeaderSignature, "sha256=not-hex")
+		}, wantErr: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signAt := tt.signAt
+			if signAt.IsZero() {
+				signAt = time.Now()
+			}
+			r := signedRequest(t, http.MethodPost, "/hooks?id=1", `{"amount":10}`, func(req *http.Request, body []byte) {
+				SignHMAC(req, body, secret, signAt)
+			})
+			if tt.mutate != nil {
+				tt.mutate(r)
+			}
+
+			verifySecret := secret
+			if tt.secret != nil {
+				verifySecret = tt.secret
+			}
+			err := VerifyHMAC(r, verifySecret, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyHMAC() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyHMACInvalidTimestamp(t *testing.T) {
+	r := signedRequest(t, http.MethodGet, "/", "", func(req *http.Request, body []byte) {
+		SignHMAC(req, body, []byte("secret"), time.Now())
+	})
+	r.Header.Set(HeaderTimestamp, "yesterday")
+
+	if err := VerifyHMAC(r, []byte("secret"), 0); err == nil {
+		t.Fatal("VerifyHMAC() accepted a non-numeric timestamp")
+	}
+}
+
+func TestVerifyHMACKeepsBody(t *testing.T) {
+	body := `{"amount":10}`
+	r := signedRequest(t, http.MethodPost, "/hooks", body, func(req *http.Request, b []byte) {
+		SignHMAC(req, b, []byte("secret"), time.Now())
+	})
+
+	if err := VerifyHMAC(r, []byte("secret"), 0); err != nil {
+		t.Fatalf("VerifyHMAC() error = %v", err)
+	}
+	got, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != body {
+		t.Errorf("body after verification = %q, want %q", got, body)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	secret := []byte("secret")
+	handler := Middleware(func(r *http.Request) error {
+		return VerifyHMAC(r, secret, 0)
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name   string
+		secret []byte
+		want   int
+	}{
+		{name: "signed", secret: secret, want: http.StatusNoContent},
+		{name: "wrong secret", secret: []byte("other"), want: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := signedRequest(t, http.MethodPost, "/hooks", "{}", func(req *http.Request, body []byte) {
+				SignHMAC(req, body, tt.secret, time.Now())
+			})
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
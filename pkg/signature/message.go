@@ -0,0 +1,324 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AlgHMACSHA256 = "hmac-sha256"
+	AlgEd25519    = "ed25519"
+
+	HeaderSignatureInput = "Signature-Input"
+	HeaderMessageSig     = "Signature"
+	HeaderContentDigest  = "Content-Digest"
+
+	defaultLabel = "sig1"
+)
+
+// Key is the key material for an HTTP Message Signature. For hmac-sha256 it
+// is the shared secret; for ed25519 it is a private key when signing and a
+// public key when verifying.
+type Key struct {
+	ID        string
+	Algorithm string
+	Secret    []byte
+	Private   ed25519.PrivateKey
+	Public    ed25519.PublicKey
+}
+
+// KeyResolver returns the verification key for a keyid found in a signature.
+type KeyResolver func(keyID string) (Key, error)
+
+// SignMessage adds RFC 9421 Signature-Input and Signature headers to req,
+// covering the method, authority, path, query and, when a body is present,
+// an RFC 9530 Content-Digest.
+func SignMessage(req *http.Request, body []byte, key Key, now time.Time) error {
+	components := []string{"@method", "@authority", "@path", "@query"}
+	if len(body) > 0 {
+		digest := sha256.Sum256(body)
+		req.Header.Set(HeaderContentDigest, "sha-256=:"+base64.StdEncoding.EncodeToString(digest[:])+":")
+		components = append(components, "content-digest")
+	}
+
+	params := signatureParams{
+		components: components,
+		created:    now.Unix(),
+		keyID:      key.ID,
+		alg:        key.Algorithm,
+	}
+
+	base, err := signatureBase(req, params)
+	if err != nil {
+		return err
+	}
+
+	var sig []byte
+	switch key.Algorithm {
+	case AlgHMACSHA256:
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(base)
+		sig = mac.Sum(nil)
+	case AlgEd25519:
+		if len(key.Private) != ed25519.PrivateKeySize {
+			return fmt.Errorf("signature: invalid ed25519 private key")
+		}
+		sig = ed25519.Sign(key.Private, base)
+	default:
+		return fmt.Errorf("signature: unsupported algorithm %q", key.Algorithm)
+	}
+
+	req.Header.Set(HeaderSignatureInput, defaultLabel+"="+params.String())
+	req.Header.Set(HeaderMessageSig, defaultLabel+"=:"+base64.StdEncoding.EncodeToString(sig)+":")
+	return nil
+}
+
+// VerifyMessage checks the first RFC 9421 signature on an incoming request.
+// The body is read and replaced so handlers can still consume it. A maxSkew of
+// zero uses DefaultMaxSkew.
+func VerifyMessage(r *http.Request, resolve KeyResolver, maxSkew time.Duration) error {
+	input := r.Header.Get(HeaderSignatureInput)
+	sigHeader := r.Header.Get(HeaderMessageSig)
+	if input == "" || sigHeader == "" {
+		return ErrMissingSignature
+	}
+
+	label, params, err := parseSignatureInput(input)
+	if err != nil {
+		return err
+	}
+
+	sig, err := findSignature(sigHeader, label)
+	if err != nil {
+		return err
+	}
+
+	if err := checkSkew(params.created, maxSkew); err != nil {
+		return err
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	if err := verifyContentDigest(r, params, body); err != nil {
+		return err
+	}
+
+	key, err := resolve(params.keyID)
+	if err != nil {
+		return fmt.Errorf("signature: unknown key %q: %w", params.keyID, err)
+	}
+	if params.alg != "" && key.Algorithm != "" && params.alg != key.Algorithm {
+		return fmt.Errorf("signature: algorithm mismatch")
+	}
+
+	base, err := signatureBase(r, params)
+	if err != nil {
+		return err
+	}
+
+	switch key.Algorithm {
+	case AlgHMACSHA256:
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write(base)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return ErrInvalidSignature
+		}
+	case AlgEd25519:
+		if len(key.Public) != ed25519.PublicKeySize || !ed25519.Verify(key.Public, base, sig) {
+			return ErrInvalidSignature
+		}
+	default:
+		return fmt.Errorf("signature: unsupported algorithm %q", key.Algorithm)
+	}
+
+	return nil
+}
+
+type signatureParams struct {
+	components []string
+	created    int64
+	keyID      string
+	alg        string
+}
+
+func (p signatureParams) String() string {
+	quoted := make([]string, len(p.components))
+	for i, c := range p.components {
+		quoted[i] = strconv.Quote(c)
+	}
+	s := "(" + strings.Join(quoted, " ") + ");created=" + strconv.FormatInt(p.created, 10)
+	if p.keyID != "" {
+		s += ";keyid=" + strconv.Quote(p.keyID)
+	}
+	if p.alg != "" {
+		s += ";alg=" + strconv.Quote(p.alg)
+	}
+	return s
+}
+
+func signatureBase(r *http.Request, params signatureParams) ([]byte, error) {
+	var b strings.Builder
+	for _, component := range params.components {
+		value, err := componentValue(r, component)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(strconv.Quote(component))
+		b.WriteString(": ")
+		b.WriteString(value)
+		b.WriteByte('\n')
+	}
+	b.WriteString(`"@signature-params": `)
+	b.WriteString(params.String())
+	return []byte(b.String()), nil
+}
+
+func componentValue(r *http.Request, component string) (string, error) {
+	switch component {
+	case "@method":
+		return strings.ToUpper(r.Method), nil
+	case "@authority":
+		host := r.Host
+		if host == "" {
+			host = r.URL.Host
+		}
+		return strings.ToLower(host), nil
+	case "@path":
+		if r.URL.EscapedPath() == "" {
+			return "/", nil
+		}
+		return r.URL.EscapedPath(), nil
+	case "@query":
+		return "?" + r.URL.RawQuery, nil
+	default:
+		if strings.HasPrefix(component, "@") {
+			return "", fmt.Errorf("signature: unsupported component %s", component)
+		}
+		values := r.Header.Values(component)
+		if len(values) == 0 {
+			return "", fmt.Errorf("signature: covered header %s is missing", component)
+		}
+		for i, v := range values {
+			values[i] = strings.TrimSpace(v)
+		}
+		return strings.Join(values, ", "), nil
+	}
+}
+
+func verifyContentDigest(r *http.Request, params signatureParams, body []byte) error {
+	covered := false
+	for _, c := range params.components {
+		if c == "content-digest" {
+			covered = true
+		}
+	}
+	if !covered {
+		if len(body) > 0 {
+			return fmt.Errorf("signature: body is not covered by the signature")
+		}
+		return nil
+	}
+
+	digest := sha256.Sum256(body)
+	expected := "sha-256=:" + base64.StdEncoding.EncodeToString(digest[:]) + ":"
+	if !hmac.Equal([]byte(r.Header.Get(HeaderContentDigest)), []byte(expected)) {
+		return fmt.Errorf("signature: content digest does not match body")
+	}
+	return nil
+}
+
+func parseSignatureInput(header string) (string, signatureParams, error) {
+	var params signatureParams
+
+	label, rest, ok := strings.Cut(strings.TrimSpace(header), "=")
+	if !ok || !strings.HasPrefix(rest, "(") {
+		return "", params, fmt.Errorf("signature: malformed %s header", HeaderSignatureInput)
+	}
+
+	end := strings.Index(rest, ")")
+	if end < 0 {
+		return "", params, fmt.Errorf("signature: malformed %s header", HeaderSignatureInput)
+	}
+	for _, item := range strings.Fields(rest[1:end]) {
+		component, err := strconv.Unquote(item)
+		if err != nil {
+			return "", params, fmt.Errorf("signature: malformed component %s", item)
+		}
+		params.components = append(params.components, component)
+	}
+
+	// Only the first signature is considered
+	rest = rest[end+1:]
+	if i := strings.Index(rest, ","); i >= 0 {
+		rest = rest[:i]
+	}
+	for _, param := range strings.Split(rest, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "created":
+			created, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return "", params, fmt.Errorf("signature: malformed created parameter")
+			}
+			params.created = created
+		case "keyid", "alg":
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return "", params, fmt.Errorf("signature: malformed %s parameter", name)
+			}
+			if name == "keyid" {
+				params.keyID = unquoted
+			} else {
+				params.alg = unquoted
+			}
+		}
+	}
+
+	if params.created == 0 {
+		return "", params, fmt.Errorf("signature: created parameter is required")
+	}
+	return label, params, nil
+}
+
+func findSignature(header, label string) ([]byte, error) {
+	for _, member := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok || name != label {
+			continue
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(value, ":"), ":")
+		sig, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, ErrInvalidSignature
+		}
+		return sig, nil
+	}
+	return nil, ErrMissingSignature
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("signature: failed to read body: %w", err)
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
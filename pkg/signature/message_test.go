@@ -0,0 +1,156 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyMessage(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hmacKey := Key{ID: "hmac-key", Algorithm: AlgHMACSHA256, Secret: []byte("shared-secret")}
+	edKey := Key{ID: "ed-key", Algorithm: AlgEd25519, Private: private}
+
+	// resolver returns the verification key for each signing key's ID
+	resolver := func(keys map[string]Key) KeyResolver {
+		return func(keyID string) (Key, error) {
+			key, ok := keys[keyID]
+			if !ok {
+				return Key{}, fmt.Errorf("no key")
+			}
+			return key, nil
+		}
+	}
+	verifyKeys := map[string]Key{
+		"hmac-key": hmacKey,
+		"ed-key":   {ID: "ed-key", Algorithm: AlgEd25519, Public: public},
+	}
+
+	tests := []struct {
+		name    string
+		key     Key
+		body    string
+		signAt  time.Time
+		keys    map[string]Key
+		mutate  func(r *http.Request)
+		wantErr error
+		failure bool // any error is expected
+	}{
+		{name: "hmac valid", key: hmacKey, body: `{"a":1}`},
+		{name: "ed25519 valid", key: edKey, body: `{"a":1}`},
+		{name: "valid without body", key: hmacKey},
+		{name: "hmac wrong secret", key: hmacKey, keys: map[string]Key{
+			"hmac-key": {Algorithm: AlgHMACSHA256, Secret: []byte("other")},
+		}, wantErr: ErrInvalidSignature},
+		{name: "ed25519 wrong public key", key: edKey, keys: map[string]Key{
+			"ed-key": {Algorithm: AlgEd25519, Public: otherPublic},
+		}, wantErr: ErrInvalidSignature},
+		{name: "tampered path", key: hmacKey, mutate: func(r *http.Request) { r.URL.Path = "/other" }, wantErr: ErrInvalidSignature},
+		{name: "tampered query", key: edKey, mutate: func(r *http.Request) { r.URL.RawQuery = "id=2" }, wantErr: ErrInvalidSignature},
+		{name: "tampered authority", key: hmacKey, mutate: func(r *http.Request) { r.Host = "evil.example.com" }, wantErr: ErrInvalidSignature},
+		{name: "tampered method", key: hmacKey, mutate: func(r *http.Request) { r.Method = http.MethodDelete }, wantErr: ErrInvalidSignature},
+		{name: "tampered body", key: hmacKey, body: `{"a":1}`, mutate: func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"a":2}`))
+		}, failure: true},
+		{name: "body added to unsigned request", key: hmacKey, mutate: func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"a":2}`))
+		}, failure: true},
+		{name: "digest replaced with body", key: hmacKey, body: `{"a":1}`, mutate: func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader(`{"a":2}`))
+			digest := sha256.Sum256([]byte(`{"a":2}`))
+			r.Header.Set(HeaderContentDigest, "sha-256=:"+base64.StdEncoding.EncodeToString(digest[:])+":")
+		}, wantErr: ErrInvalidSignature},
+		{name: "expired", key: hmacKey, signAt: time.Now().Add(-10 * time.Minute), wantErr: ErrExpired},
+		{name: "missing signature", key: hmacKey, mutate: func(r *http.Request) { r.Header.Del(HeaderMessageSig) }, wantErr: ErrMissingSignature},
+		{name: "missing signature input", key: hmacKey, mutate: func(r *http.Request) { r.Header.Del(HeaderSignatureInput) }, wantErr: ErrMissingSignature},
+		{name: "label mismatch", key: hmacKey, mutate: func(r *http.Request) {
+			r.Header.Set(HeaderMessageSig, strings.Replace(r.Header.Get(HeaderMessageSig), "sig1=", "sig2=", 1))
+		}, wantErr: ErrMissingSignature},
+		{name: "malformed signature input", key: hmacKey, mutate: func(r *http.Request) {
+			r.Header.Set(HeaderSignatureInput, "sig1")
+		}, failure: true},
+		{name: "unknown key", key: hmacKey, keys: map[string]Key{}, failure: true},
+		{name: "algorithm mismatch", key: hmacKey, keys: map[string]Key{
+			"hmac-key": {Algorithm: AlgEd25519, Public: public},
+		}, failure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signAt := tt.signAt
+			if signAt.IsZero() {
+				signAt = time.Now()
+			}
+			method := http.MethodGet
+			if tt.body != "" {
+				method = http.MethodPost
+			}
+			r := signedRequest(t, method, "/hooks?id=1", tt.body, func(req *http.Request, body []byte) {
+				if err := SignMessage(req, body, tt.key, signAt); err != nil {
+					t.Fatalf("SignMessage() error = %v", err)
+				}
+			})
+			if tt.mutate != nil {
+				tt.mutate(r)
+			}
+
+			keys := verifyKeys
+			if tt.keys != nil {
+				keys = tt.keys
+			}
+			err := VerifyMessage(r, resolver(keys), 0)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("VerifyMessage() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.failure:
+				if err == nil {
+					t.Fatal("VerifyMessage() succeeded, want an error")
+				}
+			default:
+				if err != nil {
+					t.Fatalf("VerifyMessage() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestSignMessageRejectsBadKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		key  Key
+	}{
+		{name: "unsupported algorithm", key: Key{ID: "k", Algorithm: "rsa-pss-sha512"}},
+		{name: "short ed25519 key", key: Key{ID: "k", Algorithm: AlgEd25519, Private: ed25519.PrivateKey("short")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := SignMessage(req, nil, tt.key, time.Now()); err == nil {
+				t.Error("SignMessage() succeeded, want an error")
+			}
+		})
+	}
+}